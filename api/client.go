@@ -0,0 +1,330 @@
+//Package api implements HTTP client for sea battle server
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"client.go/engine"
+)
+
+//DefaultTimeout is used by NewClient for every request
+const DefaultTimeout = 10 * time.Second
+
+//GameData represents data exchanged with server. Server describes game from the point
+//of view of requesting player: User* fields are player's shots and Bot* fields are
+//opponent's shots, even if opponent is another human player
+type GameData struct {
+	GameID       string
+	Player1      string
+	Player2      string
+	Player       string //requesting player in multiplayer rooms, empty in games against bot
+	UserLastShot string
+	BotLastShot  string
+	UserX        int
+	UserY        int
+	BotX         int
+	BotY         int
+	BotShots     []Shot //opponent's shots of last turn, resolved by server on user's fleet
+	//player's salvo in salvo games: targets in request, results of last salvo in response
+	UserShots []Shot `json:",omitempty"`
+	Turn      string
+	//salted hash of opponent's fleet, which is revealed at the end of game
+	BotCommitment string `json:",omitempty"`
+	Seed          int64  `json:",omitempty"` //seed of game's random decisions, if server uses it
+}
+
+//Values of GameData.Turn
+const (
+	TurnUser     = "user"     //player should shoot
+	TurnBot      = "bot"      //opponent shot BotX, BotY; player's client evaluates the shot (offline games and LegacyProtocol servers)
+	TurnWaiting  = "waiting"  //room is created, second player has not joined yet
+	TurnOpponent = "opponent" //opponent is shooting or evaluating player's shot
+)
+
+//Versions of game protocol, see Client.Protocol
+const (
+	//LegacyProtocol servers create games by plain username and send opponent's shots one by one
+	//with TurnBot, so client resolves them on user's fleet and reports results with ReportBotShot.
+	//They play only Classic rules
+	LegacyProtocol = 1
+	//FleetProtocol servers create games by NewGame, keep user's fleet and resolve opponent's shots themselves
+	FleetProtocol = 2
+)
+
+//ErrLegacyRules is returned when game of rules other than Classic is created on LegacyProtocol server
+var ErrLegacyRules = errors.New("server supports only Classic rules")
+
+//ServerInfo is returned by health endpoint of servers which describe themselves.
+//Servers which respond to health without it speak LegacyProtocol
+type ServerInfo struct {
+	Protocol int
+}
+
+//StatusError is returned when server responds with non-2xx status
+type StatusError struct {
+	Method     string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s request failed: %d %s", e.Method, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return fmt.Sprintf("%s request failed: %d %s: %s", e.Method, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+//Returns true if error means that server doesn't support request: it responded
+//with 404, 405 or 501 status. Such features are optional for servers
+func IsUnsupported(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	return statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusMethodNotAllowed ||
+		statusErr.StatusCode == http.StatusNotImplemented
+}
+
+//Client sends requests to sea battle server located at BaseURL
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Player     string //name of player in multiplayer room, it is sent with every shot
+	//version of game protocol spoken by server. It is found out by Health, which is
+	//requested before creating game if it is zero
+	Protocol int
+}
+
+//Creates new client for server located at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+//Checks if server is reachable and ready to accept games and sets Protocol spoken by server.
+//Server which responds without ServerInfo is LegacyProtocol one
+func (c *Client) Health(ctx context.Context) error {
+	var info ServerInfo
+
+	err := c.doPath(ctx, http.MethodGet, "health", nil, &info)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		//server is up, but it doesn't describe itself, e.g. it responds with "OK"
+		err, info = nil, ServerInfo{}
+	}
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+
+	c.Protocol = info.Protocol
+	if c.Protocol < LegacyProtocol {
+		c.Protocol = LegacyProtocol
+	}
+
+	return nil
+}
+
+//Returns body of request which creates game or room in protocol of server. LegacyProtocol servers
+//take 'legacy' body and can't play other rules than Classic. Protocol is negotiated if it is unknown yet
+func (c *Client) newGameBody(ctx context.Context, newGame NewGame, legacy interface{}) (interface{}, error) {
+	if c.Protocol == 0 {
+		if err := c.Health(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.Protocol > LegacyProtocol {
+		return newGame, nil
+	}
+
+	if newGame.Rules.Fleet != nil && newGame.Rules.Name != engine.Classic.Name {
+		return nil, fmt.Errorf("%w, not %s", ErrLegacyRules, newGame.Rules.Name)
+	}
+
+	return legacy, nil
+}
+
+//Creates new game room for player. FleetProtocol server gets user's fleet, resolves
+//bot's shots and returns them in GameData.BotShots. LegacyProtocol server gets only
+//username, and bot's shots are reported by ReportBotShot
+func (c *Client) CreateGame(ctx context.Context, newGame NewGame) (GameData, error) {
+	var game GameData
+
+	body, err := c.newGameBody(ctx, newGame, newGame.Username)
+	if err != nil {
+		return GameData{}, fmt.Errorf("create game: %w", err)
+	}
+	if err := c.do(ctx, http.MethodPost, body, &game); err != nil {
+		return GameData{}, fmt.Errorf("create game: %w", err)
+	}
+
+	return game, nil
+}
+
+//Sends user's shot to cell (x, y) of bot's field. Returned GameData contains
+//result of user's shot and, if user missed, all bot's shots of its turn
+func (c *Client) Shoot(ctx context.Context, gameID string, x int, y int) (GameData, error) {
+	var game GameData
+
+	shot := GameData{GameID: gameID, Player: c.Player, UserX: x, UserY: y, Turn: TurnUser}
+	if err := c.do(ctx, http.MethodPut, shot, &game); err != nil {
+		return GameData{}, fmt.Errorf("shoot (%d, %d): %w", x, y, err)
+	}
+
+	return game, nil
+}
+
+//Sends user's salvo to bot's field in salvo games. Returned GameData contains results
+//of user's shots in UserShots and, because turn passes after every salvo, bot's salvo
+func (c *Client) ShootSalvo(ctx context.Context, gameID string, targets []Shot) (GameData, error) {
+	var game GameData
+
+	salvo := GameData{GameID: gameID, Player: c.Player, UserShots: targets, Turn: TurnUser}
+	if err := c.do(ctx, http.MethodPut, salvo, &game); err != nil {
+		return GameData{}, fmt.Errorf("shoot salvo of %d shots: %w", len(targets), err)
+	}
+
+	return game, nil
+}
+
+//Reports result of opponent's shot, which was evaluated on user's field, to LegacyProtocol server.
+//Returned GameData contains coordinates of next opponent's shot if opponent keeps its turn
+func (c *Client) ReportBotShot(ctx context.Context, gameID string, x int, y int, result string) (GameData, error) {
+	var game GameData
+
+	shot := GameData{GameID: gameID, Player: c.Player, BotX: x, BotY: y, BotLastShot: result, Turn: TurnBot}
+	if err := c.do(ctx, http.MethodPut, shot, &game); err != nil {
+		return GameData{}, fmt.Errorf("report bot shot (%d, %d): %w", x, y, err)
+	}
+
+	return game, nil
+}
+
+//Sends user's fleet and salt at the end of game and returns the ones of opponent
+func (c *Client) Reveal(ctx context.Context, gameID string, reveal RevealData) (RevealData, error) {
+	var opponent RevealData
+
+	reveal.GameID = gameID
+	reveal.Player = c.Player
+	if err := c.doPath(ctx, http.MethodPost, "reveal", reveal, &opponent); err != nil {
+		return RevealData{}, fmt.Errorf("reveal fleet: %w", err)
+	}
+
+	return opponent, nil
+}
+
+//Returns current state of game against server's bot. It is used to resume game after restart of client
+func (c *Client) GameState(ctx context.Context, gameID string) (GameData, error) {
+	var game GameData
+
+	query := url.Values{"id": {gameID}, "player": {c.Player}}
+	if err := c.doPath(ctx, http.MethodGet, "?"+query.Encode(), nil, &game); err != nil {
+		return GameData{}, fmt.Errorf("game %s state: %w", gameID, err)
+	}
+
+	return game, nil
+}
+
+//Closes game room on server
+func (c *Client) EndGame(ctx context.Context, gameID string) error {
+	if err := c.do(ctx, http.MethodDelete, gameID, nil); err != nil {
+		return fmt.Errorf("end game %s: %w", gameID, err)
+	}
+
+	return nil
+}
+
+//Sends 'method' request with JSON encoded body to server root and decodes JSON response into 'result'
+func (c *Client) do(ctx context.Context, method string, body interface{}, result interface{}) error {
+	return c.doPath(ctx, method, "", body, result)
+}
+
+//Sends 'method' request with JSON encoded body to 'path' relative to BaseURL and decodes JSON
+//response into 'result'. Request has no body if 'body' is nil, response is not decoded if 'result' is nil
+func (c *Client) doPath(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	uri, err := c.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	var dataToSend io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		dataToSend = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, uri, dataToSend)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &StatusError{Method: method, StatusCode: response.StatusCode, Body: string(bytes.TrimSpace(data))}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+//Returns absolute uri of 'path' relative to BaseURL. Path may contain query
+func (c *Client) resolve(path string) (string, error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address %q: %w", c.BaseURL, err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return "", fmt.Errorf("invalid server address %q: scheme and host are required", c.BaseURL)
+	}
+	if path == "" {
+		return base.String(), nil
+	}
+
+	//keep last segment of base path, so "http://host/game" becomes "http://host/game/health"
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"client.go/engine"
+)
+
+//Returns test server which responds to health with given body and to other requests with empty game.
+//Body of the last request to server root is written to 'body'
+func newTestServer(t *testing.T, health string, body *[]byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			io.WriteString(w, health)
+			return
+		}
+
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		*body = data
+		json.NewEncoder(w).Encode(GameData{GameID: "1"})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHealthProtocol(t *testing.T) {
+	tests := []struct {
+		name   string
+		health string
+		want   int
+	}{
+		{"legacy text", "OK", LegacyProtocol},
+		{"legacy empty", "", LegacyProtocol},
+		{"legacy JSON", `"healthy"`, LegacyProtocol},
+		{"JSON without protocol", `{"Status": "ok"}`, LegacyProtocol},
+		{"fleet protocol", `{"Protocol": 2}`, FleetProtocol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			client := NewClient(newTestServer(t, tt.health, &body).URL)
+
+			if err := client.Health(context.Background()); err != nil {
+				t.Fatalf("Health() = %v", err)
+			}
+			if client.Protocol != tt.want {
+				t.Errorf("protocol is %d, want %d", client.Protocol, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateGameBody(t *testing.T) {
+	newGame := NewGame{Username: "alice", Rules: engine.Classic, Commitment: "hash"}
+
+	tests := []struct {
+		name   string
+		health string
+		rules  engine.Rules
+		want   interface{} //decoded body of request
+		err    error
+	}{
+		{"legacy server", "OK", engine.Classic, "alice", nil},
+		{"legacy server, default rules", "OK", engine.Rules{}, "alice", nil},
+		{"legacy server, other rules", "OK", engine.MiltonBradley, nil, ErrLegacyRules},
+		{"fleet server", `{"Protocol": 2}`, engine.MiltonBradley, map[string]interface{}{"Username": "alice"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			client := NewClient(newTestServer(t, tt.health, &body).URL)
+
+			request := newGame
+			request.Rules = tt.rules
+			_, err := client.CreateGame(context.Background(), request)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CreateGame() = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				if body != nil {
+					t.Errorf("request was sent to server: %s", body)
+				}
+				return
+			}
+
+			var got interface{}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			switch want := tt.want.(type) {
+			case string:
+				if got != want {
+					t.Errorf("body is %s, want %q", body, want)
+				}
+			case map[string]interface{}:
+				fields, ok := got.(map[string]interface{})
+				if !ok || fields["Username"] != want["Username"] || fields["Commitment"] != "hash" || fields["Rules"] == nil {
+					t.Errorf("body is %s, want NewGame", body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Subscribe opens server-sent events stream of game and calls 'handle' for every state pushed by server:
+//opponent's shots, opponent joining room, turn changes. It blocks until stream is closed by server,
+//context is cancelled or error occurs
+func (c *Client) Subscribe(ctx context.Context, gameID string, handle func(GameData)) error {
+	query := url.Values{"id": {gameID}, "player": {c.Player}}
+	uri, err := c.resolve("events?" + query.Encode())
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+
+	//stream is open during whole game, so timeout of HTTPClient can't be used
+	streamClient := &http.Client{Transport: c.HTTPClient.Transport}
+	response, err := streamClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("subscribe to game %s: %w", gameID, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("subscribe to game %s: %w", gameID,
+			&StatusError{Method: http.MethodGet, StatusCode: response.StatusCode, Body: string(bytes.TrimSpace(data))})
+	}
+
+	if err := readEvents(response.Body, func(data []byte) error {
+		var game GameData
+		if err := json.Unmarshal(data, &game); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+
+		handle(game)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("game %s events: %w", gameID, err)
+	}
+
+	return nil
+}
+
+//Reads server-sent events stream and calls 'dispatch' with data of every event.
+//Multi-line data is joined with "\n"; comments, event names and IDs are ignored
+func readEvents(r io.Reader, dispatch func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	var data []string
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if len(data) == 0 {
+				continue
+			}
+			if err := dispatch([]byte(strings.Join(data, "\n"))); err != nil {
+				return err
+			}
+			data = data[:0]
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	return scanner.Err()
+}
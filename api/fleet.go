@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+
+	"client.go/engine"
+)
+
+//ShipData represents one ship of user's fleet sent to server
+type ShipData struct {
+	Size        int
+	Orientation string //"Horizontal" or "Vertical"
+	X           int    //position of top/left deck of ship
+	Y           int
+	//offsets of decks from top/left deck for ships which are not straight
+	Shape engine.Shape `json:",omitempty"`
+}
+
+//Shot represents one shot resolved by server
+type Shot struct {
+	X      int
+	Y      int
+	Result string //"miss", "hit" or "kill"
+}
+
+//NewGame is sent to FleetProtocol server to create game or room. Server keeps user's fleet
+//and resolves opponent's shots itself, so client can't change its ships during game.
+//Player who joins room must use the same rules as room's creator
+type NewGame struct {
+	GameID     string `json:",omitempty"` //ID of room to join
+	Username   string
+	Rules      engine.Rules //board size and fleet composition
+	Fleet      []ShipData
+	Commitment string //salted hash of fleet, see package fairplay
+	//seed of game's random decisions: bot's fleet and first turn. The same seed reproduces game
+	Seed int64 `json:",omitempty"`
+}
+
+//RevealData contains fleet and salt which are revealed at the end of game to prove commitment
+type RevealData struct {
+	GameID string `json:",omitempty"`
+	Player string `json:",omitempty"`
+	Fleet  []ShipData
+	Salt   string //hex encoded
+}
+
+//Converts fleet to the form which is sent to server
+func FleetData(fleet engine.Fleet) []ShipData {
+	ships := make([]ShipData, 0, len(fleet.Array))
+
+	for _, ship := range fleet.Array {
+		ships = append(ships, ShipData{
+			Size:        ship.Size,
+			Orientation: ship.Orientation.String(),
+			X:           ship.BaseDeckPosition.X,
+			Y:           ship.BaseDeckPosition.Y,
+			Shape:       ship.Shape,
+		})
+	}
+
+	return ships
+}
+
+//Converts fleet received from server to ships
+func ShipsFromData(ships []ShipData) ([]engine.Ship, error) {
+	result := make([]engine.Ship, 0, len(ships))
+
+	for _, ship := range ships {
+		if len(ship.Shape) > 0 {
+			result = append(result, engine.NewShapedShip(ship.Shape, engine.Point{X: ship.X, Y: ship.Y}))
+			continue
+		}
+
+		var orientation engine.Orientation
+		switch ship.Orientation {
+		case engine.Horizontal.String():
+			orientation = engine.Horizontal
+		case engine.Vertical.String():
+			orientation = engine.Vertical
+		default:
+			return nil, fmt.Errorf("unknown orientation %q", ship.Orientation)
+		}
+
+		result = append(result, engine.NewShip(ship.Size, orientation, engine.Point{X: ship.X, Y: ship.Y}))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//Rating is player's rating kept by server. Servers using Glicko send rating deviation too,
+//servers using ELO leave it zero
+type Rating struct {
+	Player    string
+	Rating    float64
+	Deviation float64 `json:",omitempty"`
+	Games     int     //rated games played by player
+}
+
+//Returns rating of player, which server updates after every finished game.
+//Servers which don't rate players respond with 404 status
+func (c *Client) Rating(ctx context.Context, player string) (Rating, error) {
+	var rating Rating
+
+	query := url.Values{"player": {player}}
+	if err := c.doPath(ctx, http.MethodGet, "rating?"+query.Encode(), nil, &rating); err != nil {
+		return Rating{}, fmt.Errorf("rating of %s: %w", player, err)
+	}
+
+	return rating, nil
+}
+
+//Standing is player's line in server's leaderboard
+type Standing struct {
+	Player      string
+	Rating      float64 `json:",omitempty"`
+	Games       int
+	Wins        int
+	FewestShots int `json:",omitempty"` //the least number of shots player needed to win
+}
+
+//Returns leaderboard of server counting games of given rules finished since given time.
+//Empty rules and zero time are not sent, so server counts all games. Servers without
+//leaderboard respond with 404 status
+func (c *Client) Leaderboard(ctx context.Context, rules string, since time.Time) ([]Standing, error) {
+	var standings []Standing
+
+	query := url.Values{}
+	if rules != "" {
+		query.Set("rules", rules)
+	}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	path := "leaderboard"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	if err := c.doPath(ctx, http.MethodGet, path, nil, &standings); err != nil {
+		return nil, fmt.Errorf("leaderboard: %w", err)
+	}
+
+	return standings, nil
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+//Creates multiplayer room. Returned GameData has Turn equal to TurnWaiting until second player joins.
+//Client remembers username and sends it with following requests. User's fleet is kept by FleetProtocol server
+func (c *Client) CreateRoom(ctx context.Context, newGame NewGame) (GameData, error) {
+	var game GameData
+
+	body, err := c.newGameBody(ctx, newGame, GameData{Player1: newGame.Username, Player: newGame.Username})
+	if err != nil {
+		return GameData{}, fmt.Errorf("create room: %w", err)
+	}
+	if err := c.doPath(ctx, http.MethodPost, "rooms", body, &game); err != nil {
+		return GameData{}, fmt.Errorf("create room: %w", err)
+	}
+	c.Player = newGame.Username
+
+	return game, nil
+}
+
+//Joins multiplayer room newGame.GameID created by another player. Client remembers
+//username and sends it with following requests. User's fleet is kept by FleetProtocol server
+func (c *Client) JoinRoom(ctx context.Context, newGame NewGame) (GameData, error) {
+	var game GameData
+
+	legacy := GameData{GameID: newGame.GameID, Player2: newGame.Username, Player: newGame.Username}
+	body, err := c.newGameBody(ctx, newGame, legacy)
+	if err != nil {
+		return GameData{}, fmt.Errorf("join room %s: %w", newGame.GameID, err)
+	}
+	if err := c.doPath(ctx, http.MethodPost, "rooms/join", body, &game); err != nil {
+		return GameData{}, fmt.Errorf("join room %s: %w", newGame.GameID, err)
+	}
+	c.Player = newGame.Username
+
+	return game, nil
+}
+
+//Returns current state of multiplayer room from the point of view of client's player
+func (c *Client) RoomState(ctx context.Context, gameID string) (GameData, error) {
+	var game GameData
+
+	query := url.Values{"id": {gameID}, "player": {c.Player}}
+	if err := c.doPath(ctx, http.MethodGet, "rooms?"+query.Encode(), nil, &game); err != nil {
+		return GameData{}, fmt.Errorf("room %s state: %w", gameID, err)
+	}
+
+	return game, nil
+}
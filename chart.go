@@ -0,0 +1,102 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Space between frame of chart and its lines
+const chartPadding = 8
+
+//Returns line chart of given size with title above it. Values are drawn from left to right,
+//optional 'average' of the same length is drawn over them with another color.
+//Labels in the corner show the highest and the lowest value, formatted by 'format'
+func newLineChart(title string, values []float64, average []float64, format func(float64) string, size fyne.Size) *fyne.Container {
+	titleLabel := widget.NewLabel(title)
+	top := titleLabel.MinSize().Height
+
+	frame := canvas.NewRectangle(color.Transparent)
+	frame.StrokeColor = theme.DisabledColor()
+	frame.StrokeWidth = 1
+	frame.Move(fyne.NewPos(0, top))
+	frame.Resize(fyne.NewSize(size.Width, size.Height-top))
+
+	chart := container.NewWithoutLayout(titleLabel, frame)
+	chart.Resize(size)
+	if len(values) == 0 {
+		empty := widget.NewLabel("No games yet")
+		empty.Move(fyne.NewPos(chartPadding, top+chartPadding))
+		chart.Add(empty)
+		return chart
+	}
+
+	low, high := values[0], values[0]
+	for _, series := range [][]float64{values, average} {
+		for _, v := range series {
+			if v < low {
+				low = v
+			}
+			if v > high {
+				high = v
+			}
+		}
+	}
+	//flat line is drawn in the middle of chart
+	if high == low {
+		low, high = low-1, high+1
+	}
+
+	width := size.Width - 2*chartPadding
+	height := size.Height - top - 2*chartPadding
+	point := func(i int, v float64) fyne.Position {
+		x := width / 2
+		if len(values) > 1 {
+			x = width * float32(i) / float32(len(values)-1)
+		}
+		y := height * float32((high-v)/(high-low))
+
+		return fyne.NewPos(chartPadding+x, top+chartPadding+y)
+	}
+
+	//Draws polyline through values with dot at every value
+	draw := func(values []float64, lineColor color.Color) {
+		for i, v := range values {
+			if i > 0 {
+				line := canvas.NewLine(lineColor)
+				line.StrokeWidth = 2
+				line.Position1, line.Position2 = point(i-1, values[i-1]), point(i, v)
+				chart.Add(line)
+			}
+
+			dot := canvas.NewCircle(lineColor)
+			p := point(i, v)
+			dot.Move(fyne.NewPos(p.X-2, p.Y-2))
+			dot.Resize(fyne.NewSize(4, 4))
+			chart.Add(dot)
+		}
+	}
+	draw(values, theme.PrimaryColor())
+	if len(average) == len(values) {
+		draw(average, theme.ForegroundColor())
+	}
+
+	for _, label := range []struct {
+		text string
+		y    float32
+	}{
+		{format(high), top + 2},
+		{format(low), size.Height - theme.CaptionTextSize() - 6},
+	} {
+		text := canvas.NewText(label.text, theme.DisabledColor())
+		text.TextSize = theme.CaptionTextSize()
+		text.Move(fyne.NewPos(size.Width+4, label.y))
+		chart.Add(text)
+	}
+
+	return chart
+}
@@ -1,112 +1,156 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"math/rand"
-	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"client.go/api"
+	"client.go/engine"
+	"client.go/local"
+	"client.go/record"
+	"client.go/strategy"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
-//GameData represents data received from server
-type GameData struct {
-	GameID       string
-	Player1      string
-	Player2   	 string
-	UserLastShot string
-	BotLastShot  string
-	UserX     	 int
-	UserY     	 int
-	BotX      	 int
-	BotY      	 int
-	Turn		 string
-}
-
+//Cell is a button which renders one cell of engine.Board
 type Cell struct {
 	Button *widget.Button
 	X      int
 	Y      int
 }
 
-type Ship struct {
-	Size             int
-	Orientation      string
-	BaseDeckPosition [2]int //position of top/left corner of ship
-	DecksAlive 		 int
+//Names of ship rotations used in select, values are numbers of clockwise quarter turns.
+//Straight ships are horizontal without rotation. Names of ship types are taken from rules
+var shipRotations = map[string]int{
+	"0° (horizontal)": 0,
+	"90° (vertical)":  1,
+	"180°":            2,
+	"270°":            3,
 }
+var shipRotationNames = []string{"0° (horizontal)", "90° (vertical)", "180°", "270°"}
 
-type Fleet struct {
-	TotalDecks int
-	Size       map[string]int
-	Array      []Ship
+//shipControls contain widgets where user chooses type, rotation and mirroring of ship to place
+type shipControls struct {
+	kind     *widget.RadioGroup
+	rotation *widget.Select
+	mirror   *widget.Check
 }
 
-var serverUri string = "http://192.168.1.149:8080/"
+var serverUri string = defaultServerUri
+var apiClient *api.Client = api.NewClient(serverUri)
+
+//gameBackend creates games and answers user's shots.
+//It is implemented by both api.Client and local.Server
+type gameBackend interface {
+	CreateGame(ctx context.Context, newGame api.NewGame) (api.GameData, error)
+	Shoot(ctx context.Context, gameID string, x int, y int) (api.GameData, error)
+	ShootSalvo(ctx context.Context, gameID string, targets []api.Shot) (api.GameData, error)
+	EndGame(ctx context.Context, gameID string) error
+}
 
-var gameData GameData
-var fleet Fleet = Fleet{
-	Size: make(map[string]int, 4),
+//botShotReporter is implemented by backends which may not resolve bot's shots on user's fleet:
+//offline local.Server and api.Client of LegacyProtocol server, which send bot's shots with TurnBot.
+//User's client resolves them and reports results back
+type botShotReporter interface {
+	ReportBotShot(ctx context.Context, gameID string, x int, y int, result string) (api.GameData, error)
 }
-var userCellArray [10][10]Cell = [10][10]Cell {}
-var botCellArray [10][10]Cell = [10][10]Cell {}
+
+var backend gameBackend = apiClient //backend of current game
+
+var hintStrategy strategy.Strategy = strategy.NewProbabilityDensity(newRand())
+
+var mainWindow fyne.Window
+var statusLabel *widget.Label   //shows whose turn it is
+var opponentLabel *widget.Label //title of opponent's field, it changes when opponent joins room
+
+var gameMu sync.Mutex //guards game state, which is changed by both GUI and polling goroutine
+var stopGame func()   //stops background work of current game, if any
+var gameData api.GameData
+var userBoard *engine.Board = engine.NewBoard(engine.Classic) //user's own field with fleet, its rules are used for new game
+var botBoard *engine.Board = engine.NewBoard(engine.Classic)  //results of user's shots on bot's field
+var userCellArray [][]Cell
+var botCellArray [][]Cell
 
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "tournament" {
+		err := runTournament(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	config, err := loadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	serverUri = config.Server
+	apiClient = api.NewClient(serverUri)
+
 	initGUI()
 }
 
 func initGUI() {
 
 	window := newWindow()
+	mainWindow = window
 
 	newMainContainer(window)
+	offerResume(window)
 
 	window.ShowAndRun()
 }
 
-//Creates and sends new 'method' request to 'uri'. If used to create GET request dataToSend should
-//be NIL, because GET request requires no data to send. Method returns not unmarshaled response body,
-//so it means to use json.Unmarshal() next to sendRequest()
-func sendRequest(method string, uri string, rawDataToSend interface{}) []byte {
-	client := &http.Client{}
-	var err error
-	var request *http.Request
-	var response *http.Response
-	var dataToSend []byte
-
-	if dataToSend, err = json.Marshal(rawDataToSend); err != nil {
-		fmt.Println(err)
-	}
+//Returns new source of random numbers for strategies
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
 
-	request, err = http.NewRequest(method, uri, bytes.NewBuffer(dataToSend))
-	if err != nil {
-		fmt.Println(err)
-	}
+//Returns source of randomness which makes the same decisions of given stream for the same seed
+func seededRand(seed int64, stream local.Stream) *rand.Rand {
+	return local.SeededRand(seed, stream)
+}
 
-	//Separate method GET because it is the only method that requires no data to send, but only uri
-	if method == "GET" {
-		response, err = client.Get(uri)
-	} else {
-		response, err = client.Do(request)
+//Parses seed entered by user. New random seed is returned if text is empty
+func parseSeed(text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Now().UnixNano(), nil
 	}
 
+	seed, err := strconv.ParseInt(text, 10, 64)
 	if err != nil {
-		fmt.Println(err)
+		return 0, fmt.Errorf("invalid seed %q: it should be an integer", text)
 	}
 
-	json, err := io.ReadAll(response.Body)
-	if err != nil {
-		fmt.Println(err)
-	}
+	return seed, nil
+}
 
-	return json
+//Shows error to user in dialog window
+func showError(err error) {
+	fmt.Println(err)
+	dialog.ShowError(err, mainWindow)
 }
 
 //Initializes new main container, which contains player's nickname (for yet) and 'Start game' button
@@ -114,52 +158,204 @@ func sendRequest(method string, uri string, rawDataToSend interface{}) []byte {
 func newMainContainer(window fyne.Window) {
 	var mainContainer *fyne.Container
 
+	//Username is name of local profile, which collects results of player's games
 	usernameLabel := widget.NewLabel("Username: ")
-	usernameEntry := widget.NewEntry()
+	names, last := profileNames()
+	usernameEntry := widget.NewSelectEntry(names)
+	usernameEntry.SetText(last)
 	usernameRow := container.NewGridWithRows(2, usernameLabel, usernameEntry)
 
-	userContainer := container.NewAdaptiveGrid(10)
-	userContainer.Resize(fyne.NewSize(250, 250))
+	var startGameButton, createRoomButton, joinRoomButton *widget.Button
+	serverStatus := widget.NewLabel("")
+	serverEntry := widget.NewEntry()
+	serverEntry.SetText(serverUri)
+	//'Start game' and room buttons stay disabled until server passes health check
+	checkServerButton := widget.NewButton("Check", func() {
+		checkServer(serverEntry, serverStatus, startGameButton, createRoomButton, joinRoomButton)
+	})
+	serverEntry.OnChanged = func(string) {
+		startGameButton.Disable()
+		createRoomButton.Disable()
+		joinRoomButton.Disable()
+		serverStatus.SetText("Not checked")
+	}
+	serverRow := container.NewVBox(
+		widget.NewLabel("Server: "),
+		container.NewBorder(nil, nil, nil, checkServerButton, serverEntry),
+		serverStatus,
+	)
+	serverRow.Resize(fyne.NewSize(180, serverRow.MinSize().Height))
+
+	userContainer := container.NewWithoutLayout()
+
+	ships := &shipControls{
+		kind:     widget.NewRadioGroup(nil, func(s string) {}),
+		rotation: widget.NewSelect(shipRotationNames, func(s string) {}),
+		mirror:   widget.NewCheck("Mirrored", func(bool) {}),
+	}
+	ships.rotation.SetSelected("90° (vertical)")
+
+	//Ships are placed by click, moved by drag and drop and rotated by right click or "R" key
+	editor := newPlacementEditor(window, userContainer, ships)
+	undoButton := widget.NewButton("Undo", editor.Undo)
+	redoButton := widget.NewButton("Redo", editor.Redo)
+	shipsContainer := container.NewVBox(ships.kind, widget.NewSeparator(), ships.rotation, ships.mirror,
+		container.NewGridWithColumns(2, undoButton, redoButton))
+
+	//Rebuilds user's field and list of ship types for rules of user's board
+	showRules := func() {
+		rules := userBoard.Rules
+		size := cellSize(rules, 250)
+		userContainer.Objects = nil
+		userContainer.Layout = layout.NewGridLayoutWithColumns(rules.Width)
+		userContainer.Resize(fyne.NewSize(size*float32(rules.Width), size*float32(rules.Height)))
+		editor.cells = setButtons(userContainer, rules, "putShip", editor)
+		renderBoard(editor.cells, userBoard)
+
+		ships.kind.Options = shipNames(rules)
+		ships.kind.SetSelected(rules.Fleet[0].Name)
+		//mirroring changes only ships which are not straight
+		if rules.HasShapes() {
+			ships.mirror.Show()
+		} else {
+			ships.mirror.Hide()
+		}
+		shipsContainer.Resize(shipsContainer.MinSize())
+	}
+	showRules()
+
+	//Changing rules clears user's field, because placed fleet may not follow new rules
+	rulesSelect := widget.NewSelect(rulesOptions(), nil)
+	rulesSelect.SetSelected(userBoard.Rules.Name)
+	if _, ok := engine.PresetByName(userBoard.Rules.Name); !ok {
+		rulesSelect.SetSelected(customRulesOption)
+	}
+	rulesSelect.OnChanged = func(name string) {
+		if rules, ok := engine.PresetByName(name); ok {
+			userBoard = engine.NewBoard(rules)
+			editor.Reset()
+			showRules()
+			return
+		}
 
-	shipsSize := widget.NewRadioGroup(
-		[]string{"Single-deck ship", "Double-deck ship", "Three-deck ship", "Four-deck ship"},
-		func(s string) {})
-	shipsOrientation := widget.NewRadioGroup(
-		[]string{"Horizontal", "Vertical"},
-		func(s string) {})
-	shipsSize.SetSelected("Four-deck ship")
-	shipsOrientation.SetSelected("Vertical")
-	shipsContainer := container.NewVBox(shipsSize, widget.NewSeparator(), shipsOrientation)
+		previous := userBoard.Rules.Name
+		showCustomRulesForm(window, func(rules engine.Rules) {
+			userBoard = engine.NewBoard(rules)
+			editor.Reset()
+			showRules()
+		}, func() {
+			if _, ok := engine.PresetByName(previous); ok {
+				rulesSelect.SetSelected(previous)
+			}
+		})
+	}
+	rulesRow := container.NewVBox(widget.NewLabel("Rules: "), rulesSelect)
+	rulesRow.Resize(fyne.NewSize(150, rulesRow.MinSize().Height))
 
-	userCellArray := setButtons(userContainer, "putShip", &fleet, shipsOrientation, shipsSize)
+	//Seed of random decisions: random ships, local bot's fleet, shots and first turn, each from its own stream.
+	//New seed is chosen for every game if it is empty
+	seedEntry := widget.NewEntry()
+	seedEntry.SetPlaceHolder("Seed (random)")
+	seedEntry.Resize(fyne.NewSize(150, seedEntry.MinSize().Height))
 
 	//When the button is clicked, it sends POST request to create new game room
-	startGameButton := widget.NewButton("Start game", func() {
-		if fleet.TotalDecks == 20 && usernameEntry.Text != "" {
-			response := sendRequest("POST", serverUri, usernameEntry.Text)
-
-			if err := json.Unmarshal(response, &gameData); err != nil {
-				fmt.Println(err)
-			} else {
-				//newGameContainer(window, gameData, fleet)
-				newGameContainer(window)
-			}
-		} else if fleet.TotalDecks < 20 {
-			fmt.Println("\nYour fleet is not complete")
-		} else {
-			fmt.Println("\nYou haven't entered your nickname")
+	startGameButton = widget.NewButton("Start game", func() {
+		seed, err := parseSeed(seedEntry.Text)
+		if err != nil {
+			showError(err)
+			return
 		}
+		startGame(window, apiClient, usernameEntry.Text, seed)
 	})
 	startGameButton.Resize(fyne.NewSize(150, 50))
+	startGameButton.Disable()
+
+	botStrategy := widget.NewSelect(strategy.Names, func(string) {})
+	botStrategy.SetSelected(strategy.HuntTargetName)
+	botStrategy.Resize(fyne.NewSize(150, botStrategy.MinSize().Height))
+
+	//Starts game against local bot, server is not needed
+	offlineGameButton := widget.NewButton("Play offline", func() {
+		username := usernameEntry.Text
+		if username == "" {
+			username = "Player"
+		}
+
+		seed, err := parseSeed(seedEntry.Text)
+		if err != nil {
+			showError(err)
+			return
+		}
+		bot, err := strategy.New(botStrategy.Selected, seededRand(seed, local.BotShots))
+		if err != nil {
+			showError(err)
+			return
+		}
+		offlineStrategy = botStrategy.Selected
+		startGame(window, local.NewServer(bot), username, seed)
+	})
+	offlineGameButton.Resize(fyne.NewSize(150, 40))
+
+	//Multiplayer: one player creates room, another one joins it by its ID
+	roomEntry := widget.NewEntry()
+	roomEntry.SetPlaceHolder("Room ID")
+	roomEntry.Resize(fyne.NewSize(150, roomEntry.MinSize().Height))
+	createRoomButton = widget.NewButton("Create", func() {
+		createRoom(window, usernameEntry.Text)
+	})
+	createRoomButton.Resize(fyne.NewSize(72, 40))
+	createRoomButton.Disable()
+	joinRoomButton = widget.NewButton("Join", func() {
+		joinRoom(window, roomEntry.Text, usernameEntry.Text)
+	})
+	joinRoomButton.Resize(fyne.NewSize(72, 40))
+	joinRoomButton.Disable()
 
 	randomShipButton := widget.NewButton("Random ships", func() {
-		setFleetAutomatically(&userCellArray, &fleet)
-		mainContainer.Refresh()
+		seed, err := parseSeed(seedEntry.Text)
+		if err != nil {
+			showError(err)
+			return
+		}
+		editor.do(func() error {
+			return userBoard.PlaceRandomly(seededRand(seed, local.UserFleet))
+		})
+	})
+	randomShipButton.Resize(fyne.NewSize(150, 40))
+
+	//Saved, exported and imported fleet layouts
+	layoutsButton := widget.NewButton("Layouts", func() {
+		showLayoutsDialog(window, editor)
+	})
+	layoutsButton.Resize(fyne.NewSize(150, 40))
+
+	//Opens record of finished or exported game
+	replayButton := widget.NewButton("Replay", func() {
+		openReplay(window)
 	})
-	randomShipButton.Resize(fyne.NewSize(150, 50))
+	replayButton.Resize(fyne.NewSize(150, 50))
 
-	mainContainer = container.NewWithoutLayout(usernameRow, userContainer, startGameButton, randomShipButton, shipsContainer)
+	//Opens statistics of player entered in username entry
+	profileButton := widget.NewButton("Profile", func() {
+		if usernameEntry.Text == "" {
+			showError(errors.New("enter username to see its profile"))
+			return
+		}
+		newProfileContainer(window, usernameEntry.Text)
+	})
+	profileButton.Resize(fyne.NewSize(100, 50))
+
+	//Standings of local players or players of server
+	leaderboardButton := widget.NewButton("Leaderboard", func() {
+		newLeaderboardContainer(window)
+	})
+	leaderboardButton.Resize(fyne.NewSize(120, 50))
+
+	mainContainer = container.NewWithoutLayout(usernameRow, serverRow, rulesRow, userContainer, startGameButton,
+		randomShipButton, offlineGameButton, botStrategy, roomEntry, createRoomButton, joinRoomButton, shipsContainer, replayButton, layoutsButton, seedEntry,
+		profileButton, leaderboardButton)
 	mainContainer.Resize(fyne.NewSize(700, 500))
+	editor.root = mainContainer
 
 	verticalCenter := mainContainer.Size().Width / 2
 	usernameRow.Move(fyne.NewPos(verticalCenter-usernameRow.Size().Width/2, 30))
@@ -167,64 +363,266 @@ func newMainContainer(window fyne.Window) {
 	startGameButton.Move(fyne.NewPos(verticalCenter-startGameButton.Size().Width/2, mainContainer.Size().Height-100))
 	shipsContainer.Move(fyne.NewPos(30, userContainer.Position().Y))
 	randomShipButton.Move(fyne.NewPos(500, userContainer.Position().Y))
+	layoutsButton.Move(fyne.NewPos(500, userContainer.Position().Y+45))
+	offlineGameButton.Move(fyne.NewPos(500, userContainer.Position().Y+90))
+	botStrategy.Move(fyne.NewPos(500, userContainer.Position().Y+135))
+	roomEntry.Move(fyne.NewPos(500, userContainer.Position().Y+180))
+	createRoomButton.Move(fyne.NewPos(500, userContainer.Position().Y+220))
+	joinRoomButton.Move(fyne.NewPos(578, userContainer.Position().Y+220))
+	replayButton.Move(fyne.NewPos(500, startGameButton.Position().Y))
+	profileButton.Move(fyne.NewPos(30, startGameButton.Position().Y))
+	leaderboardButton.Move(fyne.NewPos(140, startGameButton.Position().Y))
+	serverRow.Move(fyne.NewPos(mainContainer.Size().Width-serverRow.Size().Width-20, 10))
+	rulesRow.Move(fyne.NewPos(20, 10))
+	seedEntry.Move(fyne.NewPos(20, rulesRow.Position().Y+rulesRow.Size().Height+4))
+
+	checkServer(serverEntry, serverStatus, startGameButton, createRoomButton, joinRoomButton)
 
 	mainContainer.Refresh()
 	window.SetContent(mainContainer)
 	window.SetTitle("Sea Battle")
 }
 
+//Returns true if user's fleet is complete and nickname is entered
+func readyToStart(username string) bool {
+	if !userBoard.IsComplete() {
+		fmt.Println("\nYour fleet is not complete")
+		return false
+	}
+	if username == "" {
+		fmt.Println("\nYou haven't entered your nickname")
+		return false
+	}
+
+	return true
+}
+
+//Creates new game on given backend and opens game container. Seed is sent to backend,
+//which uses it for random decisions of the game
+func startGame(window fyne.Window, b gameBackend, username string, seed int64) {
+	if !readyToStart(username) {
+		return
+	}
+
+	newGame, err := newGameRequest(username, "")
+	if err != nil {
+		showError(err)
+		return
+	}
+	newGame.Seed = seed
+
+	game, err := b.CreateGame(context.Background(), newGame)
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	backend = b
+	gameData = game
+	isRoom = false
+	resetGame()
+	newGameContainer(window)
+
+	//remote server pushes bot's moves, so user sees them as soon as they are made
+	if client, ok := b.(*api.Client); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopGame = cancel
+		go watchGame(ctx, client, game.GameID, false)
+	} else if game.Turn == api.TurnBot || len(game.BotShots) > 0 {
+		//bot won coin toss and made the first turn
+		go func() {
+			gameMu.Lock()
+			defer gameMu.Unlock()
+
+			applyBotShots(gameData.BotShots)
+			if err := playBotTurn(context.Background()); err != nil {
+				showError(err)
+			}
+			updateStatus()
+			autosave()
+			checkGameOver()
+		}()
+	}
+}
+
+//Sends health request to server entered in 'serverEntry'. If server is reachable, it becomes
+//current server and buttons, which require server, are enabled
+func checkServer(serverEntry *widget.Entry, status *widget.Label, onlineButtons ...*widget.Button) {
+	uri := serverEntry.Text
+	for _, button := range onlineButtons {
+		button.Disable()
+	}
+	status.SetText("Checking...")
+
+	client := api.NewClient(uri)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), api.DefaultTimeout)
+		defer cancel()
+
+		err := client.Health(ctx)
+		if serverEntry.Text != uri {
+			return //address was changed while request was in progress
+		}
+		if err != nil {
+			fmt.Println(err)
+			status.SetText("Unavailable")
+			return
+		}
+
+		serverUri = uri
+		apiClient = client
+		if client.Protocol == api.LegacyProtocol {
+			status.SetText("Online (Classic only)")
+		} else {
+			status.SetText("Online")
+		}
+		for _, button := range onlineButtons {
+			button.Enable()
+		}
+	}()
+}
+
 //Initializes new game container, which contains game details: both user and bot field,
 //'End game' button (for yet), to close current game and open new main container
-//func newGameContainer(window fyne.Window, gameData GameData, fleet Fleet) {
 func newGameContainer(window fyne.Window) {
-	//Size of cell fields depends on size of window
-	fieldSize := window.Canvas().Size().Width/2 - 75
+	//Size of cell fields depends on size of window and board size of rules.
+	//Sidebar with opponent's fleet takes place on the right
+	fieldSize := (window.Canvas().Size().Width-trackerWidth)/2 - 65
+	rules := userBoard.Rules
+	size := cellSize(rules, fieldSize)
 
-	userContainer := container.NewAdaptiveGrid(10)
-	botContainer := container.NewAdaptiveGrid(10)
+	userContainer := container.NewGridWithColumns(rules.Width)
+	botContainer := container.NewGridWithColumns(rules.Width)
 
 	userContainer.Move(fyne.NewPos(50, 80))
 	botContainer.Move(fyne.NewPos(fieldSize+100, 80))
-	userContainer.Resize(fyne.NewSize(fieldSize, fieldSize))
-	botContainer.Resize(fyne.NewSize(fieldSize, fieldSize))
+	userContainer.Resize(fyne.NewSize(size*float32(rules.Width), size*float32(rules.Height)))
+	botContainer.Resize(fyne.NewSize(size*float32(rules.Width), size*float32(rules.Height)))
 
-	player1Label := widget.NewLabel(gameData.Player1 + "'s field:")
-	player2Label := widget.NewLabel(gameData.Player2 + "'s field:")
+	username, opponent := playerNames()
+	player1Label := widget.NewLabel(username + "'s field:")
+	opponentLabel = widget.NewLabel(opponent + "'s field:")
 	player1Label.Move(fyne.NewPos(50, 30))
-	player2Label.Move(fyne.NewPos(fieldSize+100, 30))
+	opponentLabel.Move(fyne.NewPos(fieldSize+100, 30))
 
-	//Setting cells in fields
-	userCellArray = setButtons(userContainer, "", nil, nil, nil)
-	botCellArray = setButtons(botContainer, "shoot", nil, nil, nil)
+	statusLabel = widget.NewLabel("")
+	statusLabel.Move(fyne.NewPos(50, window.Canvas().Size().Height-90))
+	updateStatus()
 
-	for _, ship := range fleet.Array {
-		drawShip(ship, &userCellArray)
+	//seed reproduces the game, so it is shown for bug reports and sharing
+	seedLabel := widget.NewLabel("")
+	if gameData.Seed != 0 {
+		seedLabel.SetText(fmt.Sprintf("Seed: %d", gameData.Seed))
 	}
+	seedLabel.Move(fyne.NewPos(fieldSize+100, window.Canvas().Size().Height-90))
+
+	//Setting cells in fields
+	userCellArray = setButtons(userContainer, rules, "", nil)
+	botCellArray = setButtons(botContainer, rules, "shoot", nil)
+	tracker := newFleetTracker(window.Canvas().Size().Height - 200)
+	tracker.Move(fyne.NewPos(window.Canvas().Size().Width-trackerWidth-15, 80))
+
+	//Overlay shades opponent's cells by probability of ship, it helps to learn and to debug strategies
+	heatmap := newHeatmap(rules, botContainer.Size())
+	heatmap.Move(botContainer.Position())
+	heatmapCheck := widget.NewCheck("Heatmap (estimate)", func(on bool) {
+		//polling and events goroutines change bot's field, which heatmap is computed from
+		gameMu.Lock()
+		defer gameMu.Unlock()
+
+		showHeatmap = on
+		updateHeatmap()
+	})
+	heatmapCheck.SetChecked(showHeatmap)
+	heatmapCheck.Resize(heatmapCheck.MinSize())
+	heatmapCheck.Move(fyne.NewPos(window.Canvas().Size().Width-heatmapCheck.Size().Width-15, 30))
+	renderBoard(userCellArray, userBoard)
+	renderBotField()
 
 	endGameButton := widget.NewButton("End game", func() {
-		sendRequest("DELETE", serverUri, gameData.GameID)
-		gameData = GameData{}
-		fleet = Fleet{Size: make(map[string]int, 4)}
-		userCellArray = [10][10]Cell {}
-		botCellArray = [10][10]Cell {}
+		leaveGame()
 		newMainContainer(window)
 	})
 	endGameButton.Move(fyne.NewPos(window.Canvas().Size().Width/2-50, window.Canvas().Size().Height-100))
 	endGameButton.Resize(fyne.NewSize(100, 50))
 
+	//Highlights cell which hint strategy would shoot next
+	hintButton := widget.NewButton("Hint", func() {
+		if !gameFinished {
+			showHint(hintStrategy.NextShot(botBoard))
+		}
+	})
+	hintButton.Move(fyne.NewPos(window.Canvas().Size().Width/2+60, window.Canvas().Size().Height-100))
+	hintButton.Resize(fyne.NewSize(100, 50))
+
+	exportButton := widget.NewButton("Export", func() {
+		exportRecord(window)
+	})
+	exportButton.Move(fyne.NewPos(window.Canvas().Size().Width/2+170, window.Canvas().Size().Height-100))
+	exportButton.Resize(fyne.NewSize(100, 50))
+
 	gameContainer := container.NewWithoutLayout(
 		player1Label,
-		player2Label,
+		opponentLabel,
+		statusLabel,
+		seedLabel,
 		userContainer,
 		botContainer,
+		heatmap,
+		heatmapCheck,
+		tracker,
 		endGameButton,
+		hintButton,
+		exportButton,
 	)
 
+	//In salvo games user selects cells on bot's field and fires them all at once
+	if rules.IsSalvo() {
+		fireButton := widget.NewButton("Fire", func() {
+			go func() {
+				gameMu.Lock()
+				defer gameMu.Unlock()
+
+				if gameFinished {
+					fmt.Println("\nGame is over")
+					return
+				}
+				if gameData.Turn != api.TurnUser {
+					fmt.Println("\nWait for your turn")
+					return
+				}
+				if err := fireSalvo(); err != nil {
+					showError(err)
+				}
+				updateStatus()
+				autosave()
+				checkGameOver()
+				botContainer.Refresh()
+			}()
+		})
+		fireButton.Move(fyne.NewPos(window.Canvas().Size().Width/2-160, window.Canvas().Size().Height-100))
+		fireButton.Resize(fyne.NewSize(100, 50))
+		gameContainer.Add(fireButton)
+	}
+
 	//Adding containers to window
 	gameContainer.Refresh()
 	window.SetContent(gameContainer)
 
 	window.SetTitle("Sea Battle: Game ID: " + gameData.GameID)
+	autosave()
+}
+
+//Clears results of shots of previous game before new game starts
+func resetGame() {
+	botBoard = engine.NewBoard(userBoard.Rules)
+	salvoTargets = nil
+	gameFinished = false
+
+	username, opponent := playerNames()
+	gameRecord = record.New(gameData.GameID, userBoard.Rules, userBoard.Fleet.Array)
+	gameRecord.User, gameRecord.Opponent = username, opponent
+	gameRecord.Seed = gameData.Seed
 }
 
 //Sets a new window
@@ -239,43 +637,57 @@ func newWindow() fyne.Window {
 	return window
 }
 
-//Sets cells on container. Parameter 'field' determines on which field cells should be set
-func setButtons(container *fyne.Container, listener string, fleet *Fleet,
-	shipOrientation *widget.RadioGroup, shipSize *widget.RadioGroup) [10][10]Cell {
+//Sets cells of field of given rules on container. Parameter 'listener' determines what happens
+//when cell is clicked, 'editor' is used only by "putShip" listener
+func setButtons(container *fyne.Container, rules engine.Rules, listener string, editor *placementEditor) [][]Cell {
+	cellArray := make([][]Cell, rules.Height)
 
-	var cellArray [10][10]Cell = [10][10]Cell{}
-
-	for x := 0; x < 10; x++ {
-		for y := 0; y < 10; y++ {
+	for x := 0; x < rules.Height; x++ {
+		cellArray[x] = make([]Cell, rules.Width)
+		for y := 0; y < rules.Width; y++ {
 			cell := Cell{
 				X: x,
 				Y: y,
 			}
 
+			//cells of user's field are edited by placement editor, which handles drag and drop too
+			if listener == "putShip" {
+				button := newPlacementButton(cell, editor)
+				cell.Button = &button.Button
+				container.Add(button)
+				cellArray[x][y] = cell
+				continue
+			}
+
 			cell.Button = widget.NewButton("", func() {
 				switch listener {
-				case "putShip":
-					validateAreaForShip(&cellArray, cell, fleet,
-						shipOrientation.Selected, shipSize.Selected)
-
-					container.Refresh()
 				case "shoot":
-					if cell.Button.Text == "" {
-						fmt.Println("\n\n\n")
-						for i := 1;; i++ {
-							
-							shoot(cell, cellArray)
-							analyzeResponse()
-		
+					//shot is sent in background, so window doesn't freeze while opponent plays its turn
+					go func() {
+						gameMu.Lock()
+						defer gameMu.Unlock()
+
+						if gameFinished {
+							fmt.Println("\nGame is over")
+						} else if gameData.Turn == api.TurnWaiting || gameData.Turn == api.TurnOpponent {
+							fmt.Println("\nWait for your turn")
+						} else if botBoard.Rules.IsSalvo() {
+							toggleSalvoTarget(cell)
+							updateStatus()
+						} else if botBoard.At(cell.point()) == engine.CellEmpty {
+							fmt.Println()
+							if err := shoot(cell); err != nil {
+								showError(err)
+							}
+							updateStatus()
+							autosave()
+							checkGameOver()
+
 							container.Refresh()
-							
-							fmt.Println(gameData)
-							
-							if gameData.Turn == "user" { break }
+						} else {
+							fmt.Println("\nYou were shooting this cell already")
 						}
-					} else {
-						fmt.Println("\nYou were shooting this cell already")
-					}
+					}()
 				}
 			})
 
@@ -287,564 +699,197 @@ func setButtons(container *fyne.Container, listener string, fleet *Fleet,
 	return cellArray
 }
 
-func analyzeResponse() {
-	switch gameData.UserLastShot {
-	case "miss":
-		botCellArray[gameData.UserX][gameData.UserY].Button.Text = "*"
-	case "hit":
-		botCellArray[gameData.UserX][gameData.UserY].Button.Text = "X"
-	case "kill":
-		botCellArray[gameData.UserX][gameData.UserY].Button.Text = "X"
-		coverKilledShip(&botCellArray, &botCellArray[gameData.UserX][gameData.UserY])
-	}
-
-	if gameData.Turn == "bot" {
-		analyzeBotShot(&gameData, &fleet)
-
-		switch gameData.BotLastShot {
-		case "miss":
-			userCellArray[gameData.BotX][gameData.BotY].Button.Text = "*"
-		case "hit":
-			userCellArray[gameData.BotX][gameData.BotY].Button.Text = "X"
-		case "kill":
-			userCellArray[gameData.BotX][gameData.BotY].Button.Text = "X"
-			coverKilledShip(&userCellArray, &userCellArray[gameData.BotX][gameData.BotY])
-		}
-
-		userCellArray[gameData.BotX][gameData.BotY].Button.Refresh()
-	}
-}
-
-//Returns true if ship killed and false if ship still alive
-func isShipKilled(ship Ship) bool {
-	return ship.DecksAlive == 0
+//Returns position of cell on board
+func (cell Cell) point() engine.Point {
+	return engine.Point{X: cell.X, Y: cell.Y}
 }
 
-//Returns true if bot hit user's ship, false if not
-func isShipHit(ship Ship, botX int, botY int) bool {
-	shipX := ship.BaseDeckPosition[0]
-	shipY := ship.BaseDeckPosition[1]
-
-	for i := 0; i < ship.Size; i++ {
-		switch ship.Orientation {
-		case "Vertical":
-			if shipX+i == botX && shipY == botY {
-				return true
-			}
-		case "Horizontal":
-			if shipX == botX && shipY+i == botY {
-				return true
+//Sets text of every cell according to board state: "*" - miss, "X" - hit deck, "#" - deck,
+//"<" or "^" - base deck of horizontal or vertical ship, "+" - base deck of ship of other shape
+func renderBoard(cellArray [][]Cell, board *engine.Board) {
+	for x := range cellArray {
+		for y := range cellArray[x] {
+			cell := cellArray[x][y]
+			if cell.Button == nil {
+				continue
 			}
-		}
-	}
-
-	return false
-}
 
-//Edits fleet and ship parameters when user hit or kill ship
-func editFleet(ship *Ship, fleet *Fleet) {
-	ship.DecksAlive--
-	fleet.TotalDecks--
-
-	//Replacing edited ship in slice
-	shipArrayCopy := fleet.Array
-	fleet.Array = nil
-	for _, s := range shipArrayCopy {
-		if s.BaseDeckPosition == ship.BaseDeckPosition {
-			fleet.Array = append(fleet.Array, *ship)
-		} else {
-			fleet.Array = append(fleet.Array, s)
+			cell.Button.Importance = widget.MediumImportance
+			cell.Button.SetText(cellText(board, cell.point()))
 		}
 	}
 }
 
-//Analyzes bot shot. Sets variables gameData.Turn and gameData.BotLastShot
-func analyzeBotShot(gameData *GameData, fleet *Fleet) {
-	x := gameData.BotX
-	y := gameData.BotY
-
-	for _, ship := range fleet.Array {
-		if isShipHit(ship, x, y) {
-			fmt.Println(ship)
-			fmt.Println("\tBot hit user's ship")
-			editFleet(&ship, fleet)
-			
-			if isShipKilled(ship) {
-				fmt.Println("\tBot killed user's ship")
-				gameData.Turn = "bot";
-				gameData.BotLastShot = "kill"
-				return
+func cellText(board *engine.Board, p engine.Point) string {
+	switch board.At(p) {
+	case engine.CellMiss:
+		return "*"
+	case engine.CellHit:
+		return "X"
+	case engine.CellDeck:
+		if ship, ok := board.ShipAt(p); ok && ship.BaseDeckPosition == p {
+			if ship.Shape != nil {
+				return "+"
 			}
-			gameData.Turn = "bot";
-			gameData.BotLastShot = "hit"
-			return
-		}
-	}
-	fmt.Println("\tBot missed")
-	gameData.Turn = "user";
-	gameData.BotLastShot = "miss"
-}
-
-//Covers empty cells with "*" text around whole ship when player kills it
-func coverKilledShip(cellArray *[10][10]Cell, cell *Cell) {
-	shipOrientation := getKilledShipOrientation(cellArray, cell)
-	decksBehind, decksInFront := getKilledShipSize(cellArray, cell, shipOrientation)
-
-	coverOneDeck(cellArray, cell.X, cell.Y)
-
-	for step := 1; step <= decksBehind; step++ {
-		switch shipOrientation {
-		case "Vertical":
-			coverOneDeck(cellArray, cell.X-step, cell.Y)
-		case "Horizontal":
-			coverOneDeck(cellArray, cell.X, cell.Y-step)
-		}
-	}
-
-	for step := 1; step <= decksInFront; step++ {
-		switch shipOrientation {
-		case "Vertical":
-			coverOneDeck(cellArray, cell.X+step, cell.Y)
-		case "Horizontal":
-			coverOneDeck(cellArray, cell.X, cell.Y+step)
+			if ship.Orientation == engine.Vertical {
+				return "^"
+			}
+			return "<"
 		}
+		return "#"
 	}
-}
 
-//Returns orientation of killed ship
-func getKilledShipOrientation(cellArray *[10][10]Cell, cell *Cell) string {
-	if cell.cellsAroundAreClear(cellArray) {
-		return "Single-deck ship"
-	}
-	
-	if cell.X + 1 <= 9 {
-		textBelow := cellArray[cell.X+1][cell.Y].Button.Text
-		if textBelow == "X" || textBelow == "#" || textBelow == "<" || textBelow == "^" {
-			return "Vertical"
-		}
-	}
-	if cell.X - 1 >= 0 {
-		textAbove := cellArray[cell.X-1][cell.Y].Button.Text
-		if textAbove == "X" || textAbove == "#" || textAbove == "<" || textAbove == "^" {
-			return "Vertical"
-		}
-	}
-	if cell.Y + 1 <= 9 {
-		textRight := cellArray[cell.X][cell.Y+1].Button.Text
-		if textRight == "X" || textRight == "#" || textRight == "<" || textRight == "^" {
-			return "Horizontal"
-		}
-	}
-	if cell.Y - 1 >= 0 {
-		textLeft  := cellArray[cell.X][cell.Y-1].Button.Text
-		if textLeft == "X" || textLeft == "#" || textLeft == "<" || textLeft == "^" {
-			return "Horizontal"
-		}
-	}
-	
 	return ""
 }
 
-//Returns two values: number of cell that located behind last hit cell of killed ship
-//and number of cells that located in front of last hit cell of killed ship. Last hit cell
-//not included in both values
-func getKilledShipSize(cellArray *[10][10]Cell, cell *Cell, shipOrientation string) (int, int) {
-	var decksInFront int	//number of decks that located in front of last hit cell of killed ship
-	var decksBehind int 	//number of decks that located behind last hit cell of killed ship
-
-	var checkBehind bool 	= true	//'true' - program checks for decks behind; 'false' - no
-	var checkInFront bool 	= true	//'true' - program checks for decks if front; 'false' - no
-	for step := 1; step <= 3; step++ {
-		switch shipOrientation {
-			case "Vertical": {
-				if cell.X + step <= 9 && checkInFront {
-					if (cellArray[cell.X + step][cell.Y].Button.Text != "*" &&
-						cellArray[cell.X + step - 1][cell.Y].Button.Text != "*") &&
-					   (cellArray[cell.X + step][cell.Y].Button.Text != "" &&
-					   cellArray[cell.X + step - 1][cell.Y].Button.Text != "") {
-				
-						decksInFront++
-					} else {
-						checkInFront = false
-					}
-				}
-				if cell.X - step >= 0 && checkBehind {
-					if (cellArray[cell.X - step][cell.Y].Button.Text != "*" &&
-					   cellArray[cell.X - step + 1][cell.Y].Button.Text != "*") &&
-					   (cellArray[cell.X - step][cell.Y].Button.Text != "" &&
-					   cellArray[cell.X - step + 1][cell.Y].Button.Text != "") {
-					
-						decksBehind++
-					} else {
-						checkBehind = false
-					}
-				}
-			}
-			
-			case "Horizontal": {
-				if cell.Y + step <= 9 && checkInFront {
-					if (cellArray[cell.X][cell.Y + step].Button.Text != "*" &&  
-					   cellArray[cell.X][cell.Y + step - 1].Button.Text != "*") &&
-					   (cellArray[cell.X][cell.Y + step].Button.Text != "" && 
-					   cellArray[cell.X][cell.Y + step - 1].Button.Text != "") {
-					
-						decksInFront++
-					} else {
-						checkInFront = false
-					}
-				}
-				if cell.Y - step >= 0 && checkBehind {
-					if (cellArray[cell.X][cell.Y - step].Button.Text != "*" &&
-					   cellArray[cell.X][cell.Y - step + 1].Button.Text != "*") &&
-					   (cellArray[cell.X][cell.Y - step].Button.Text != "" &&
-					   cellArray[cell.X][cell.Y - step + 1].Button.Text != "") {
-				
-						decksBehind++
-					} else {
-						checkBehind = false
-					}
-				}
-			}
-		}
+//Highlights cell of bot's field until next render
+func showHint(p engine.Point) {
+	button := botCellArray[p.X][p.Y].Button
+	if button == nil || botBoard.At(p) != engine.CellEmpty {
+		return
 	}
-	return decksBehind, decksInFront
-}
-
-//Covers one piece of killed ship
-func coverOneDeck(cellArray *[10][10]Cell, cellX int, cellY int) {
-	for x := -1; x <= 1; x++ {
-		for y := -1; y <= 1; y++ {
-			if cellX == 0 && x == -1 || cellX == 9 && x == 1 {
-				break
-			}
-
-			if cellY == 0 && y == -1 || cellY == 9 && y == 1 {
-				continue
-			}
-
-			if x == 0 && y == 0 {
-				continue
-			} 
-			
-			if cellArray[cellX+x][cellY+y].Button.Text != "X" &&
-			   cellArray[cellX+x][cellY+y].Button.Text != "<" &&
-			   cellArray[cellX+x][cellY+y].Button.Text != "^" {
 
-				cellArray[cellX+x][cellY+y].Button.Text = "*"
-			}
+	button.Importance = widget.HighImportance
+	button.Refresh()
+}
 
-			cellArray[cellX+x][cellY+y].Button.Refresh()
+//Records results of user's shot or salvo on bot's field
+func analyzeResponse() {
+	if len(gameData.UserShots) > 0 {
+		for _, shot := range gameData.UserShots {
+			markUserShot(engine.Point{X: shot.X, Y: shot.Y}, shot.Result)
 		}
+	} else {
+		markUserShot(engine.Point{X: gameData.UserX, Y: gameData.UserY}, gameData.UserLastShot)
 	}
-}
 
-//Sends PUT request to server when user hits cell in bot's field.
-//Response saved in GameData struct
-func shoot(cell Cell, cellArray [10][10]Cell) {
-	gameData.UserX = cell.X
-	gameData.UserY = cell.Y
-	response := sendRequest("PUT", serverUri, gameData)
-
-	//Unmarshal received data from PUT request
-	if err := json.Unmarshal(response, &gameData); err != nil {
-		fmt.Println(err)
-	}
+	renderBotField()
 }
 
-//Handler for 'Random ships' button. Clears all current data about user fleet
-//and sets new ships with random location and orientation
-func setFleetAutomatically(cellArray *[10][10]Cell, fleet *Fleet) {
-	for x := 0; x < 10; x++ {
-		for y := 0; y < 10; y++ {
-			eraseShip(cellArray[x][y], cellArray, fleet)
-		}
+//Records result of one user's shot, reported by server, on bot's field
+func markUserShot(p engine.Point, shotResult string) {
+	result, err := engine.ParseShotResult(shotResult)
+	if err != nil {
+		return
 	}
 
-	var shipOrientation string
-	var shipSize string
-
-	rand.Seed(int64(time.Now().Nanosecond()))
-
-	for fleet.TotalDecks != 20 && len(fleet.Array) < 10 {
-		switch rand.Intn(2) {
-		case 0:
-			shipOrientation = "Vertical"
-		case 1:
-			shipOrientation = "Horizontal"
-		}
-
-		shipSize = whichShipToSet(fleet)
-
-		x := rand.Intn(10)
-		y := rand.Intn(10)
-
-		validateAreaForShip(cellArray, cellArray[x][y], fleet, shipOrientation, shipSize)
+	if botBoard.InBounds(p) && botBoard.At(p) == engine.CellEmpty {
+		recordUserShot(p, result)
 	}
+	botBoard.Mark(p, result)
 }
 
-//Analyzes current fleet and returns which ship should be set now
-func whichShipToSet(fleet *Fleet) string {
-	var singleDeck, doubleDeck, threeDeck, fourDeck int
-
-	for _, ship := range fleet.Array {
-		switch ship.Size {
-		case 1:
-			singleDeck++
-		case 2:
-			doubleDeck++
-		case 3:
-			threeDeck++
-		case 4:
-			fourDeck++
+//Applies bot's shots resolved by server to user's field. Server's result is the
+//authoritative one; a different local result means that boards are out of sync
+func applyBotShots(shots []api.Shot) {
+	for _, shot := range shots {
+		p := engine.Point{X: shot.X, Y: shot.Y}
+
+		result, err := userBoard.Receive(p)
+		if errors.Is(err, engine.ErrAlreadyShot) {
+			result, _ = userBoard.ResultAt(p)
+		} else if err != nil {
+			fmt.Println(err)
+			continue
+		} else {
+			recordBotShot(p, result)
 		}
-	}
 
-	if fourDeck < 1 {
-		return "Four-deck ship"
-	} else if threeDeck < 2 {
-		return "Three-deck ship"
-	} else if doubleDeck < 3 {
-		return "Double-deck ship"
-	} else if singleDeck < 4 {
-		return "Single-deck ship"
+		if result.String() != shot.Result {
+			fmt.Printf("\tServer resolved bot's shot (%d, %d) as %s, but user's field shows %s\n",
+				shot.X, shot.Y, shot.Result, result)
+		}
 	}
 
-	return ""
+	renderBoard(userCellArray, userBoard)
 }
 
-//Validates area for supposed ship. Each stage has it's own job: to check if ship
-//collides another ship or field borders; or to check if fleet have empty space for
-//supposed ship. Also method can delete existing ship if user hits cell, which is base
-//deck of existing ship.
-//func validateAreaForShip(cellArray *[10][10]Cell, cell Cell, fleet *Fleet) {
-func validateAreaForShip(cellArray *[10][10]Cell, cell Cell,
-	fleet *Fleet, shipOrientation string, shipSize string) {
-
-	//terminate method if something gone wrong with ship's parameters
-	if shipOrientation == "" || shipSize == "" {
-		fmt.Println("\nSize and/or orientation values are empty")
-		return
-	}
-
-	//delete ship if pressed cell is BaseDeck (left/top piece of ship)
-	//if pressed cell have "#" text but it is not BaseDeck, call will be ignored
-	if cell.Button.Text == "^" || cell.Button.Text == "<" {
-		eraseShip(cell, cellArray, fleet)
-		return
-
-		//check if supposed ship colliding something
-		//if branch - ship collided something; terminate method
-		//else branch - cell around supposed ship are clear; next validation stage
-		//Backup params: cellArray, "Single-deck ship", "Horizontal"
-	} else if !cell.shipCollision(cellArray, shipSize, shipOrientation) {
-		return
-
-		//check if fleet have free space for supposed ship
-		//if branch - there aren't free space; terminate method
-		//else branch - there free space; ship can be placed
-		//Backup params: "Single-deck ship", *fleet
-	} else if !fleetHaveFreeSpace(shipSize, *fleet) {
-		return
-
-		//program draws specified ship on field if all validations above allows to
-		//Backup params: "Horizontal", "Single-deck ship", cell, fleet), cellArray
+//Analyzes bot shot. Sets variables gameData.Turn and gameData.BotLastShot
+func analyzeBotShot(gameData *api.GameData) {
+	p := engine.Point{X: gameData.BotX, Y: gameData.BotY}
+	result, err := userBoard.Receive(p)
+	if errors.Is(err, engine.ErrAlreadyShot) {
+		//the same shot is received again, e.g. if its result was not delivered
+		result, _ = userBoard.ResultAt(p)
+	} else if err != nil {
+		fmt.Println(err)
 	} else {
-		drawShip(newShip(shipOrientation, shipSize, cell, fleet), cellArray)
+		recordBotShot(p, result)
 	}
-}
-
-//Deletes ship from both field and Fleet struct
-func eraseShip(cell Cell, cellArray *[10][10]Cell, fleet *Fleet) {
-	x := cell.X
-	y := cell.Y
-
-	for _, ship := range fleet.Array {
-		if ship.BaseDeckPosition[0] == x && ship.BaseDeckPosition[1] == y {
-			switch ship.Size {
-			case 1:
-				fleet.Size["Single-deck ship"] -= 1
-			case 2:
-				fleet.Size["Double-deck ship"] -= 1
-			case 3:
-				fleet.Size["Three-deck ship"] -= 1
-			case 4:
-				fleet.Size["Four-deck ship"] -= 1
-			}
 
-			fleet.TotalDecks -= ship.Size
-
-			for i := 0; i < ship.Size; i++ {
-				switch ship.Orientation {
-				case "Vertical":
-					cellArray[x+i][y].Button.Text = ""
-				case "Horizontal":
-					cellArray[x][y+i].Button.Text = ""
-				}
-			}
-
-			//Removing ship from slice
-			shipArrayCopy := fleet.Array
-			fleet.Array = nil
-			for _, s := range shipArrayCopy {
-				if s != ship {
-					fleet.Array = append(fleet.Array, s)
-				}
-			}
-
-			break
-		}
-	}
-}
-
-//Draws a ship in cell array
-func drawShip(ship Ship, cellArray *[10][10]Cell) {
-	x := ship.BaseDeckPosition[0]
-	y := ship.BaseDeckPosition[1]
-
-	for i := 0; i < ship.Size; i++ {
-		switch ship.Orientation {
-		case "Horizontal":
-			{
-				if i == 0 {
-					cellArray[x][y+i].Button.Text = "<"
-				} else {
-					cellArray[x][y+i].Button.Text = "#"
-				}
-			}
-		case "Vertical":
-			{
-				if i == 0 {
-					cellArray[x+i][y].Button.Text = "^"
-				} else {
-					cellArray[x+i][y].Button.Text = "#"
-				}
-			}
-		}
+	switch result {
+	case engine.ShotKill:
+		fmt.Println("\tBot killed user's ship")
+		gameData.Turn = "bot"
+	case engine.ShotHit:
+		fmt.Println("\tBot hit user's ship")
+		gameData.Turn = "bot"
+	default:
+		fmt.Println("\tBot missed")
+		gameData.Turn = "user"
 	}
+	gameData.BotLastShot = result.String()
 }
 
-//Creates new ship with given parameters
-func newShip(shipOrientation string, shipSize string, cell Cell, fleet *Fleet) Ship {
-	var ship Ship
+//Sends user's shot to backend when user hits cell in bot's field and applies bot's shots
+//resolved by server to user's field. Offline backend doesn't resolve bot's shots: while bot
+//keeps its turn, client resolves its shots and reports their results back. Responses saved in gameData
+func shoot(cell Cell) error {
+	ctx := context.Background()
 
-	switch shipSize {
-	case "Single-deck ship":
-		ship.Size = 1
-	case "Double-deck ship":
-		ship.Size = 2
-	case "Three-deck ship":
-		ship.Size = 3
-	case "Four-deck ship":
-		ship.Size = 4
+	game, err := backend.Shoot(ctx, gameData.GameID, cell.X, cell.Y)
+	if err != nil {
+		return err
 	}
+	gameData = game
+	analyzeResponse()
+	applyBotShots(gameData.BotShots)
 
-	ship.BaseDeckPosition = [2]int{cell.X, cell.Y}
-	ship.Orientation = shipOrientation
-	ship.DecksAlive = ship.Size
-
-	fleet.Size[shipSize] += 1
-	fleet.TotalDecks += ship.Size
-	fleet.Array = append(fleet.Array, ship)
-
-	return ship
+	return playBotTurn(ctx)
 }
 
-//Validation method. Returns true if cells around hit cell are clear, and false if cells aren't
-func (cell Cell) cellsAroundAreClear(cellArray *[10][10]Cell) bool {
-	for x := -1; x <= 1; x++ {
-		for y := -1; y <= 1; y++ {
-			if x == 0 && y == 0 {
-				continue
-			}
-
-			//going to next iteration if cell.X is 0 or 9
-			//to get rid of index out of range exception
-			if cell.X == 0 && x == -1 || cell.X == 9 && x == 1 {
-				break
-			}
-
-			//going to next iteration if cell.Y is 0 or 9
-			//to get rid of index out of range exception
-			if cell.Y == 0 && y == -1 || cell.Y == 9 && y == 1 {
-				continue
-			}
-
-			//false returns if atleast one of cells around hit cell have:
-			//"#" or "<" or "^" text when we are working on user's field
-			//"X" when we are working on bot's field
-			text := cellArray[cell.X+x][cell.Y+y].Button.Text
-			if text == "#" || text == "<" || text == "^" || text == "X" {
-				return false
-			}
-		}
+//Resolves bot's shots on user's field while bot keeps its turn and reports their results
+//to offline backend or LegacyProtocol server. FleetProtocol server resolves bot's shots itself
+//and never passes turn to TurnBot, so nothing is done for it. Bot's turn ends when user's fleet
+//is sunk, even if backend sends another shot
+func playBotTurn(ctx context.Context) error {
+	reporter, ok := backend.(botShotReporter)
+	if !ok {
+		return nil
 	}
 
-	return true
-}
+	for gameData.Turn == api.TurnBot && !userBoard.FleetDestroyed() {
+		fmt.Println(gameData)
 
-//Validation method. Returns false if ship collided something,
-//and true if ship doesn't and can be placed in area
-func (cell Cell) shipCollision(cellArray *[10][10]Cell, shipSize string, shipOrientation string) bool {
-	var size int
-	var validationResult bool
-
-	switch shipSize {
-	case "Single-deck ship":
-		size = 1
-	case "Double-deck ship":
-		size = 2
-	case "Three-deck ship":
-		size = 3
-	case "Four-deck ship":
-		size = 4
-	}
-
-	for i := 0; i < size; i++ {
-		if shipOrientation == "Vertical" {
-			if cell.X+i <= 9 {
-				validationResult = cellArray[cell.X+i][cell.Y].cellsAroundAreClear(cellArray)
-			} else {
-				validationResult = false
-			}
-		} else if shipOrientation == "Horizontal" {
-			if cell.Y+i <= 9 {
-				validationResult = cellArray[cell.X][cell.Y+i].cellsAroundAreClear(cellArray)
-			} else {
-				validationResult = false
-			}
-		}
+		analyzeBotShot(&gameData)
+		renderBoard(userCellArray, userBoard)
 
-		if validationResult == false {
+		if gameData.Turn == "user" {
 			break
 		}
+
+		game, err := reporter.ReportBotShot(ctx, gameData.GameID, gameData.BotX, gameData.BotY, gameData.BotLastShot)
+		if err != nil {
+			return err
+		}
+		gameData = game
 	}
 
-	return validationResult
+	return nil
 }
 
-//Validation method. Returns true if number of existing ships
-//with same size is not greater than maximum amount.
-//Return false if there are no free space in fleet's
-//'Size map[string]int' or if error occured
-func fleetHaveFreeSpace(shipSize string, fleet Fleet) bool {
-	var maxAmount int //used to contain maximum number for specified type of ship
-
-	switch shipSize {
-	case "Single-deck ship":
-		maxAmount = 4
-	case "Double-deck ship":
-		maxAmount = 3
-	case "Three-deck ship":
-		maxAmount = 2
-	case "Four-deck ship":
-		maxAmount = 1
-	default: //default used if something go wrong with value of ship's size
-		return false
+//Handler for cells of user's field during placement. Deletes ship if pressed cell is
+//its base deck (left/top piece of ship), otherwise tries to place new ship there
+func validateAreaForShip(cell Cell, shipName string, rotation string, mirror bool) error {
+	//terminate method if something gone wrong with ship's parameters
+	spec, ok := shipSpec(shipName)
+	if !ok || rotation == "" {
+		return errors.New("type and/or rotation values are empty")
 	}
 
-	if fleet.Size[shipSize] < maxAmount {
-		return true
+	if _, ok := userBoard.RemoveAt(cell.point()); ok {
+		return nil
 	}
 
-	return false
+	return userBoard.Place(spec.NewShip(cell.point(), shipRotations[rotation], mirror))
 }
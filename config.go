@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//Default server address used when it is not set in flags, environment or config file
+const defaultServerUri = "http://localhost:8080/"
+
+//Environment variable which overrides server address from config file
+const serverEnvVariable = "SEABATTLE_SERVER"
+
+//Config contains client settings. Values are taken from command-line flags,
+//environment variables and config file, in that order of precedence
+type Config struct {
+	Server string `json:"server"`
+}
+
+//Returns path of default config file: <user config dir>/seabattle/config.json
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "seabattle", "config.json")
+}
+
+//Parses command-line arguments and builds config. Missing config file is not an error
+func loadConfig(args []string) (Config, error) {
+	config := Config{Server: defaultServerUri}
+
+	flags := flag.NewFlagSet("seabattle", flag.ContinueOnError)
+	configPath := flags.String("config", defaultConfigPath(), "path to JSON config file")
+	server := flags.String("server", "", "server address, overrides $"+serverEnvVariable+" and config file")
+	if err := flags.Parse(args); err != nil {
+		return config, err
+	}
+
+	if *configPath != "" {
+		if err := readConfigFile(*configPath, &config); err != nil {
+			return config, err
+		}
+	}
+
+	if value := os.Getenv(serverEnvVariable); value != "" {
+		config.Server = value
+	}
+
+	if *server != "" {
+		config.Server = *server
+	}
+
+	return config, nil
+}
+
+//Reads JSON config file over values already set in config
+func readConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+//Package engine contains sea battle rules: board model, ship placement
+//and shot resolution. It has no dependency on GUI, so bots, tests and
+//headless tools can use the same rules as the client
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+//CellState represents what is known about one cell of board
+type CellState int
+
+const (
+	CellEmpty CellState = iota //nothing is known about the cell
+	CellMiss                   //cell was shot and it is empty, or it can't contain a ship
+	CellHit                    //deck of ship which was hit
+	CellDeck                   //deck of ship which is not hit yet
+)
+
+var (
+	ErrCollision  = errors.New("ship collides another ship or field borders")
+	ErrFleetFull  = errors.New("fleet have no free space for ship")
+	ErrOutOfBoard = errors.New("point is out of board")
+	ErrNoShip     = errors.New("there is no ship with base deck in this point")
+)
+
+//Board represents one field. Player's own board contains fleet and all its decks,
+//opponent's board contains only results of player's shots
+type Board struct {
+	Rules Rules
+	Cells [][]CellState //Cells[x][y], Rules.Height rows of Rules.Width cells
+	Fleet Fleet
+}
+
+//Creates new empty board of size given by rules
+func NewBoard(rules Rules) *Board {
+	cells := make([][]CellState, rules.Height)
+	for x := range cells {
+		cells[x] = make([]CellState, rules.Width)
+	}
+
+	return &Board{Rules: rules, Cells: cells, Fleet: NewFleet()}
+}
+
+//Returns true if point is located on board
+func (board *Board) InBounds(p Point) bool {
+	return p.X >= 0 && p.X < board.Rules.Height && p.Y >= 0 && p.Y < board.Rules.Width
+}
+
+//Returns all points of board row by row
+func (board *Board) Points() []Point {
+	points := make([]Point, 0, board.Rules.Width*board.Rules.Height)
+
+	for x := 0; x < board.Rules.Height; x++ {
+		for y := 0; y < board.Rules.Width; y++ {
+			points = append(points, Point{x, y})
+		}
+	}
+
+	return points
+}
+
+//Returns state of cell in given point
+func (board *Board) At(p Point) CellState {
+	return board.Cells[p.X][p.Y]
+}
+
+//Returns ship which occupies given point
+func (board *Board) ShipAt(p Point) (Ship, bool) {
+	if i := board.Fleet.indexAt(p); i >= 0 {
+		return board.Fleet.Array[i], true
+	}
+
+	return Ship{}, false
+}
+
+//Returns copy of board which can be changed without changing original board
+func (board *Board) Clone() *Board {
+	clone := NewBoard(board.Rules)
+	for x := range board.Cells {
+		copy(clone.Cells[x], board.Cells[x])
+	}
+
+	clone.Fleet.TotalDecks = board.Fleet.TotalDecks
+	for size, amount := range board.Fleet.Size {
+		clone.Fleet.Size[size] = amount
+	}
+	clone.Fleet.Array = append([]Ship(nil), board.Fleet.Array...)
+
+	return clone
+}
+
+//Removes all ships and shots from board
+func (board *Board) Clear() {
+	*board = *NewBoard(board.Rules)
+}
+
+//Validation method. Returns true if number of existing ships of the same kind
+//as given ship is less than maximum amount given by rules
+func (board *Board) HaveFreeSpace(ship Ship) bool {
+	kind := ship.Kind()
+	placed := 0
+
+	for _, other := range board.Fleet.Array {
+		if other.Kind() == kind {
+			placed++
+		}
+	}
+
+	return placed < board.Rules.CountByKind()[kind]
+}
+
+//Returns true if every ship of fleet is placed
+func (board *Board) IsComplete() bool {
+	return len(board.Fleet.Array) == board.Rules.ShipCount()
+}
+
+//Analyzes current fleet and returns type of ship which should be set now.
+//Biggest ships go first. Returns false if fleet is complete
+func (board *Board) NextShip() (ShipSpec, bool) {
+	specs := append([]ShipSpec(nil), board.Rules.Fleet...)
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Size > specs[j].Size })
+
+	for _, spec := range specs {
+		if board.HaveFreeSpace(spec.NewShip(Point{}, 0, false)) {
+			return spec, true
+		}
+	}
+
+	return ShipSpec{}, false
+}
+
+//Returns true if whole fleet is placed and sunk. On opponent's board it means
+//that every ship of rules is known as sunk, so player won
+func (board *Board) FleetDestroyed() bool {
+	return board.IsComplete() && board.Fleet.TotalDecks == 0
+}
+
+//ShipStatus tells how many ships of one type of rules are sunk
+type ShipStatus struct {
+	Spec ShipSpec
+	Sunk int
+}
+
+//Returns status of every ship type of rules, in order of rules. Sunk ships are matched with
+//types by their shape, so on opponent's board, where only sunk ships are known, it shows which
+//ships are still afloat. Ships of the same shape are counted as sunk in the first of their types
+func (board *Board) FleetStatus() []ShipStatus {
+	sunk := make(map[string]int)
+	for _, ship := range board.Fleet.Array {
+		if ship.IsKilled() {
+			sunk[ship.Kind()]++
+		}
+	}
+
+	status := make([]ShipStatus, len(board.Rules.Fleet))
+	for i, spec := range board.Rules.Fleet {
+		kind := spec.Kind()
+		n := sunk[kind]
+		if n > spec.Count {
+			n = spec.Count
+		}
+		sunk[kind] -= n
+		status[i] = ShipStatus{Spec: spec, Sunk: n}
+	}
+
+	return status
+}
+
+//Validation method. Returns true if cells around given point, which can't contain
+//decks of another ship by adjacency rule, contain no decks
+func (board *Board) cellsAroundAreClear(p Point) bool {
+	for _, n := range board.Neighbours(p) {
+		if state := board.At(n); state == CellDeck || state == CellHit {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Returns points around given point which are located on board and can't contain deck
+//of another ship by adjacency rule. With no-touch rule these are all 8 points around it
+func (board *Board) Neighbours(p Point) []Point {
+	offsets := board.Rules.Adjacency.forbiddenOffsets()
+	points := make([]Point, 0, len(offsets))
+
+	for _, offset := range offsets {
+		if n := (Point{p.X + offset.X, p.Y + offset.Y}); board.InBounds(n) {
+			points = append(points, n)
+		}
+	}
+
+	return points
+}
+
+//Validation method. Returns false if ship collides another ship or field borders,
+//and true if ship doesn't and can be placed in area
+func (board *Board) CanPlace(ship Ship) bool {
+	for _, p := range ship.Cells() {
+		if !board.InBounds(p) || board.At(p) != CellEmpty || !board.cellsAroundAreClear(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Places ship on board if it doesn't collide anything and fleet have free space for it
+func (board *Board) Place(ship Ship) error {
+	if !board.CanPlace(ship) {
+		return ErrCollision
+	}
+	if !board.HaveFreeSpace(ship) {
+		return ErrFleetFull
+	}
+
+	board.put(ship)
+
+	return nil
+}
+
+//Places ship without any checks
+func (board *Board) put(ship Ship) {
+	for _, p := range ship.Cells() {
+		board.Cells[p.X][p.Y] = CellDeck
+	}
+	board.Fleet.add(ship)
+}
+
+//Deletes ship which base deck is located in given point from both board and fleet.
+//Returns false if there is no such ship
+func (board *Board) RemoveAt(p Point) (Ship, bool) {
+	i := board.Fleet.indexAt(p)
+	if i < 0 || board.Fleet.Array[i].BaseDeckPosition != p {
+		return Ship{}, false
+	}
+
+	ship := board.Fleet.remove(i)
+	for _, c := range ship.Cells() {
+		board.Cells[c.X][c.Y] = CellEmpty
+	}
+
+	return ship, true
+}
+
+//Replaces ship which base deck is located in given point with another ship,
+//e.g. moved or rotated one. Board is not changed if new ship can't be placed
+func (board *Board) Replace(base Point, ship Ship) error {
+	old, ok := board.RemoveAt(base)
+	if !ok {
+		return ErrNoShip
+	}
+
+	if err := board.Place(ship); err != nil {
+		board.Place(old)
+		return err
+	}
+
+	return nil
+}
+
+//Clears board and sets complete fleet in uniformly random positions.
+//Board is not changed if fleet can't be placed
+func (board *Board) PlaceRandomly(r *rand.Rand) error {
+	return NewGenerator(StyleUniform, r).Place(board)
+}
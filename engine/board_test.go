@@ -0,0 +1,134 @@
+package engine
+
+import "testing"
+
+func TestFleetStatusWithTouchingShips(t *testing.T) {
+	//destroyer is sunk first, then cruiser which touches it
+	board := markedBoard(t, MiltonBradley, []shot{
+		{Point{0, 0}, ShotHit}, {Point{0, 1}, ShotKill},
+		{Point{0, 2}, ShotHit}, {Point{0, 3}, ShotHit}, {Point{0, 4}, ShotKill},
+		{Point{1, 4}, ShotHit},
+	})
+
+	want := map[string]int{"Carrier": 0, "Battleship": 0, "Cruiser": 1, "Submarine": 0, "Destroyer": 1}
+	status := board.FleetStatus()
+	if len(status) != len(MiltonBradley.Fleet) {
+		t.Fatalf("got status of %d ship types, want %d", len(status), len(MiltonBradley.Fleet))
+	}
+	for i, s := range status {
+		if s.Spec.Name != MiltonBradley.Fleet[i].Name {
+			t.Errorf("status %d is of %q, want %q", i, s.Spec.Name, MiltonBradley.Fleet[i].Name)
+		}
+		if s.Sunk != want[s.Spec.Name] {
+			t.Errorf("%s: sunk %d, want %d", s.Spec.Name, s.Sunk, want[s.Spec.Name])
+		}
+	}
+}
+
+func TestFleetStatusOfSameKinds(t *testing.T) {
+	//cruiser and submarine have the same shape, so two sunk ships of it fill both types
+	board := markedBoard(t, MiltonBradley, []shot{
+		{Point{0, 0}, ShotHit}, {Point{0, 1}, ShotHit}, {Point{0, 2}, ShotKill},
+		{Point{1, 0}, ShotHit}, {Point{1, 1}, ShotHit}, {Point{1, 2}, ShotKill},
+	})
+
+	for _, s := range board.FleetStatus() {
+		want := 0
+		if s.Spec.Size == 3 {
+			want = 1
+		}
+		if s.Sunk != want {
+			t.Errorf("%s: sunk %d, want %d", s.Spec.Name, s.Sunk, want)
+		}
+	}
+}
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		ships []Ship //ships placed before
+		ship  Ship
+		want  error
+	}{
+		{"empty board", Classic, nil, NewShip(4, Horizontal, Point{0, 0}), nil},
+		{"out of board", Classic, nil, NewShip(4, Horizontal, Point{0, 8}), ErrCollision},
+		{"overlap", Classic, []Ship{NewShip(2, Vertical, Point{3, 3})}, NewShip(3, Horizontal, Point{4, 2}), ErrCollision},
+		{"touching by side", Classic, []Ship{NewShip(2, Vertical, Point{3, 3})}, NewShip(2, Vertical, Point{3, 4}), ErrCollision},
+		{"touching by corner", Classic, []Ship{NewShip(1, Vertical, Point{3, 3})}, NewShip(1, Vertical, Point{4, 4}), ErrCollision},
+		{"touching allowed", MiltonBradley, []Ship{NewShip(2, Vertical, Point{3, 3})}, NewShip(3, Vertical, Point{3, 4}), nil},
+		{"no ships of this kind left", Classic, []Ship{NewShip(4, Horizontal, Point{0, 0})}, NewShip(4, Horizontal, Point{9, 0}), ErrFleetFull},
+		{"kind isn't in rules", Classic, nil, NewShip(5, Horizontal, Point{0, 0}), ErrFleetFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewBoard(tt.rules)
+			for _, ship := range tt.ships {
+				if err := board.Place(ship); err != nil {
+					t.Fatalf("place %v: %v", ship, err)
+				}
+			}
+			before := board.Clone()
+
+			err := board.Place(tt.ship)
+			if err != tt.want {
+				t.Fatalf("Place() = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				if len(board.Fleet.Array) != len(before.Fleet.Array) {
+					t.Errorf("fleet is changed by failed placement")
+				}
+				return
+			}
+			for _, p := range tt.ship.Cells() {
+				if board.At(p) != CellDeck {
+					t.Errorf("cell %v is %v, want deck", p, board.At(p))
+				}
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name string
+		base Point
+		ship Ship
+		want error
+	}{
+		{"move", Point{0, 0}, NewShip(4, Horizontal, Point{5, 0}), nil},
+		{"rotate", Point{0, 0}, NewShip(4, Horizontal, Point{0, 0}).Rotate(), nil},
+		{"no ship in point", Point{0, 1}, NewShip(4, Horizontal, Point{5, 0}), ErrNoShip},
+		{"collision", Point{0, 0}, NewShip(4, Vertical, Point{2, 3}), ErrCollision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewBoard(Classic)
+			old := NewShip(4, Horizontal, Point{0, 0})
+			other := NewShip(1, Horizontal, Point{3, 3})
+			for _, ship := range []Ship{old, other} {
+				if err := board.Place(ship); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := board.Replace(tt.base, tt.ship)
+			if err != tt.want {
+				t.Fatalf("Replace() = %v, want %v", err, tt.want)
+			}
+
+			want := tt.ship
+			if err != nil {
+				want = old
+			}
+			if ship, ok := board.ShipAt(want.BaseDeckPosition); !ok || !samePoints(ship.Cells(), want.Cells()) {
+				t.Errorf("ship at %v is %v, want %v", want.BaseDeckPosition, ship, want)
+			}
+			if len(board.Fleet.Array) != 2 {
+				t.Errorf("fleet has %d ships, want 2", len(board.Fleet.Array))
+			}
+		})
+	}
+}
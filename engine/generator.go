@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+//PlacementStyle tells generator which positions of ships are preferred
+type PlacementStyle int
+
+const (
+	StyleUniform PlacementStyle = iota //all allowed positions are equally likely
+	StyleEdges                         //ships hug borders of field
+	StyleSpread                        //ships are placed far from each other
+	StyleAntiBot                       //ships avoid cells which probability density bots shoot first
+)
+
+//StyleNames contains names of all placement styles, indexed by PlacementStyle
+var StyleNames = [...]string{
+	StyleUniform: "uniform",
+	StyleEdges:   "edges",
+	StyleSpread:  "spread",
+	StyleAntiBot: "anti-bot",
+}
+
+//Returns "uniform", "edges", "spread" or "anti-bot"
+func (style PlacementStyle) String() string {
+	if style < 0 || int(style) >= len(StyleNames) {
+		return fmt.Sprintf("PlacementStyle(%d)", int(style))
+	}
+
+	return StyleNames[style]
+}
+
+//Converts name returned by String to PlacementStyle
+func ParseStyle(s string) (PlacementStyle, error) {
+	for style, name := range StyleNames {
+		if name == s {
+			return PlacementStyle(style), nil
+		}
+	}
+
+	return StyleUniform, fmt.Errorf("unknown placement style %q", s)
+}
+
+//ErrNoLayout is returned when generator can't find layout of fleet
+var ErrNoLayout = errors.New("fleet can't be placed on board")
+
+//Limits of generator's work, so it always terminates
+const (
+	randomAttempts    = 50    //attempts of random placement before backtracking search
+	backtrackingSteps = 20000 //positions tried by backtracking search
+)
+
+//Generator places complete fleet on board. Ships are placed one by one, biggest first, each one
+//in random allowed position chosen with weights of placement style. When random placement runs
+//into dead end several times, backtracking search is used. Both have limited number of attempts
+type Generator struct {
+	Style PlacementStyle
+	rand  *rand.Rand
+}
+
+//Creates generator of given style. Layouts are reproducible for the same seed of 'r'
+func NewGenerator(style PlacementStyle, r *rand.Rand) *Generator {
+	return &Generator{Style: style, rand: r}
+}
+
+//generatedShip is one ship of fleet with all its rotations and mirrors placed at (0, 0)
+type generatedShip struct {
+	spec     ShipSpec
+	variants []Ship
+}
+
+//Clears board and places complete fleet of its rules. Board is not changed if
+//layout isn't found, which is possible if fleet is too big for board
+func (g *Generator) Place(board *Board) error {
+	ships := fleetOrder(board.Rules)
+	heat := placementHeat(board.Rules)
+
+	for attempt := 0; attempt < randomAttempts; attempt++ {
+		placed := NewBoard(board.Rules)
+		if g.placeRandomly(placed, ships, heat) {
+			*board = *placed
+			return nil
+		}
+	}
+
+	placed := NewBoard(board.Rules)
+	steps := backtrackingSteps
+	if g.backtrack(placed, ships, Point{-1, -1}, &steps) {
+		*board = *placed
+		return nil
+	}
+
+	return ErrNoLayout
+}
+
+//Places ships one by one in weighted random positions. Returns false if some ship has no position
+func (g *Generator) placeRandomly(board *Board, ships []generatedShip, heat [][]int) bool {
+	for _, ship := range ships {
+		candidates := positions(board, ship)
+		if len(candidates) == 0 {
+			return false
+		}
+
+		weights := make([]float64, len(candidates))
+		total := 0.0
+		for i, ship := range candidates {
+			weights[i] = g.weight(board, ship, heat)
+			total += weights[i]
+		}
+
+		choice := g.rand.Float64() * total
+		i := 0
+		for ; i < len(candidates)-1 && choice >= weights[i]; i++ {
+			choice -= weights[i]
+		}
+		board.put(candidates[i])
+	}
+
+	return true
+}
+
+//Tries every position of every ship in random order until whole fleet is placed or steps are over.
+//Ships of the same type are interchangeable, so each of them is placed after the previous one
+//in row-major order: 'after' is base deck of previous ship of the same type
+func (g *Generator) backtrack(board *Board, ships []generatedShip, after Point, steps *int) bool {
+	if len(ships) == 0 {
+		return true
+	}
+
+	var candidates []Ship
+	for _, ship := range positions(board, ships[0]) {
+		if base := ship.BaseDeckPosition; base.X > after.X || base.X == after.X && base.Y > after.Y {
+			candidates = append(candidates, ship)
+		}
+	}
+	g.rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	for _, ship := range candidates {
+		if *steps <= 0 {
+			return false
+		}
+		*steps--
+
+		next := Point{-1, -1}
+		if len(ships) > 1 && ships[1].spec.Name == ships[0].spec.Name {
+			next = ship.BaseDeckPosition
+		}
+
+		board.put(ship)
+		if g.backtrack(board, ships[1:], next, steps) {
+			return true
+		}
+		board.RemoveAt(ship.BaseDeckPosition)
+	}
+
+	return false
+}
+
+//Returns weight of ship's position for generator's style, positions with bigger weight are more likely
+func (g *Generator) weight(board *Board, ship Ship, heat [][]int) float64 {
+	cells := ship.Cells()
+
+	switch g.Style {
+	case StyleEdges:
+		border := 1
+		for _, p := range cells {
+			if p.X == 0 || p.Y == 0 || p.X == board.Rules.Height-1 || p.Y == board.Rules.Width-1 {
+				border++
+			}
+		}
+		return float64(border * border * border)
+	case StyleSpread:
+		distance := board.Rules.Width + board.Rules.Height
+		for _, other := range board.Fleet.Array {
+			for _, deck := range other.Cells() {
+				for _, p := range cells {
+					if d := chebyshev(p, deck); d < distance {
+						distance = d
+					}
+				}
+			}
+		}
+		return float64(distance * distance * distance)
+	case StyleAntiBot:
+		sum := 0
+		for _, p := range cells {
+			sum += heat[p.X][p.Y]
+		}
+		//density is cubed, so that cold positions are much more likely
+		average := float64(sum) / float64(len(cells))
+		return 1 / (average * average * average)
+	}
+
+	return 1
+}
+
+//Returns all positions where ship can be placed now
+func positions(board *Board, generated generatedShip) []Ship {
+	var ships []Ship
+
+	for _, p := range board.Points() {
+		for _, ship := range generated.variants {
+			ship.BaseDeckPosition = p
+			if board.CanPlace(ship) {
+				ships = append(ships, ship)
+			}
+		}
+	}
+
+	return ships
+}
+
+//Returns every ship of fleet with its variants, biggest ships go first
+func fleetOrder(rules Rules) []generatedShip {
+	var ships []generatedShip
+	for _, spec := range rules.Fleet {
+		var variants []Ship
+		for _, shape := range spec.Offsets().Variants() {
+			variants = append(variants, NewShapedShip(shape, Point{}))
+		}
+
+		for i := 0; i < spec.Count; i++ {
+			ships = append(ships, generatedShip{spec: spec, variants: variants})
+		}
+	}
+	sort.SliceStable(ships, func(i, j int) bool { return ships[i].spec.Size > ships[j].spec.Size })
+
+	return ships
+}
+
+//Returns number of positions of fleet's ships covering every cell of empty board.
+//Probability density bots shoot cells with bigger numbers first
+func placementHeat(rules Rules) [][]int {
+	board := NewBoard(rules)
+	heat := make([][]int, rules.Height)
+	for x := range heat {
+		heat[x] = make([]int, rules.Width)
+	}
+
+	for _, spec := range rules.Fleet {
+		for _, variant := range spec.Offsets().Variants() {
+			for _, p := range board.Points() {
+				ship := NewShapedShip(variant, p)
+				if !board.CanPlace(ship) {
+					continue
+				}
+				for _, c := range ship.Cells() {
+					heat[c.X][c.Y] += spec.Count
+				}
+			}
+		}
+	}
+
+	return heat
+}
+
+//Returns distance between points, where diagonal step is as long as straight one
+func chebyshev(a Point, b Point) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dx > dy {
+		return dx
+	}
+
+	return dy
+}
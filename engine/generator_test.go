@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestGeneratorPlacesCompleteFleet(t *testing.T) {
+	for _, rules := range Presets {
+		for style := range StyleNames {
+			style := PlacementStyle(style)
+			t.Run(rules.Name+"/"+style.String(), func(t *testing.T) {
+				board := NewBoard(rules)
+				if err := NewGenerator(style, rand.New(rand.NewSource(1))).Place(board); err != nil {
+					t.Fatalf("Place() = %v", err)
+				}
+				if !board.IsComplete() {
+					t.Fatalf("fleet has %d ships, want %d", len(board.Fleet.Array), rules.ShipCount())
+				}
+
+				//every ship must be accepted by the same checks as ship placed by hand
+				if _, err := PlaceLayout(rules, board.Layout()); err != nil {
+					t.Errorf("generated layout is invalid: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestGeneratorIsReproducible(t *testing.T) {
+	first, second := NewBoard(Polyomino), NewBoard(Polyomino)
+	if err := NewGenerator(StyleSpread, rand.New(rand.NewSource(42))).Place(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGenerator(StyleSpread, rand.New(rand.NewSource(42))).Place(second); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := first.Layout().ASCII()
+	b, _ := second.Layout().ASCII()
+	if a != b {
+		t.Errorf("layouts of the same seed differ:\n%s\n%s", a, b)
+	}
+}
+
+func TestGeneratorTerminatesOnImpossibleRules(t *testing.T) {
+	rules := Rules{
+		Name:      "Impossible",
+		Width:     6,
+		Height:    6,
+		Fleet:     []ShipSpec{{Name: "Four-deck ship", Size: 4, Count: 3}, {Name: "Single-deck ship", Size: 1, Count: 6}},
+		Adjacency: NoTouch,
+	}
+	board := NewBoard(rules)
+	if err := board.Place(NewShip(1, Horizontal, Point{0, 0})); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	err := NewGenerator(StyleUniform, rand.New(rand.NewSource(1))).Place(board)
+	if err != ErrNoLayout {
+		t.Fatalf("Place() = %v, want %v", err, ErrNoLayout)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("generator worked %v", elapsed)
+	}
+	if len(board.Fleet.Array) != 1 || board.At(Point{0, 0}) != CellDeck {
+		t.Errorf("board is changed after failure")
+	}
+}
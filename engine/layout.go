@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Layout is arrangement of fleet on board, which can be saved and placed again
+type Layout struct {
+	Width  int
+	Height int
+	Ships  []Ship
+}
+
+//Characters of ASCII layout: empty cell and decks of ships, one letter for each ship
+const (
+	layoutEmpty   = '.'
+	layoutLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+//Returns layout of fleet placed on board
+func (board *Board) Layout() Layout {
+	ships := make([]Ship, 0, len(board.Fleet.Array))
+	for _, ship := range board.Fleet.Array {
+		ships = append(ships, NewShapedShip(ship.Offsets(), ship.BaseDeckPosition))
+	}
+
+	return Layout{Width: board.Rules.Width, Height: board.Rules.Height, Ships: ships}
+}
+
+//Returns new board of given rules with fleet of layout. Every ship is checked like
+//it was placed by hand, so layout must fit board size, adjacency and fleet of rules.
+//Ships of layout may come from file, so they are checked by Rules.CheckShip first
+func PlaceLayout(rules Rules, layout Layout) (*Board, error) {
+	if layout.Width != rules.Width || layout.Height != rules.Height {
+		return nil, fmt.Errorf("layout for %dx%d board doesn't fit %dx%d board",
+			layout.Width, layout.Height, rules.Width, rules.Height)
+	}
+
+	board := NewBoard(rules)
+	for _, ship := range layout.Ships {
+		if err := rules.CheckShip(ship); err != nil {
+			return nil, fmt.Errorf("ship at %d:%d: %w", ship.BaseDeckPosition.X, ship.BaseDeckPosition.Y, err)
+		}
+		if err := board.Place(NewShapedShip(ship.Offsets(), ship.BaseDeckPosition)); err != nil {
+			return nil, fmt.Errorf("ship at %d:%d: %w", ship.BaseDeckPosition.X, ship.BaseDeckPosition.Y, err)
+		}
+	}
+
+	return board, nil
+}
+
+//Returns layout as grid of characters, one line for each row of board: "." is empty cell,
+//decks of each ship are marked with its own letter, so ships touching each other are distinguished
+func (layout Layout) ASCII() (string, error) {
+	if len(layout.Ships) > len(layoutLetters) {
+		return "", fmt.Errorf("layout of %d ships can't be written as ASCII", len(layout.Ships))
+	}
+
+	rows := make([][]byte, layout.Height)
+	for x := range rows {
+		rows[x] = []byte(strings.Repeat(string(layoutEmpty), layout.Width))
+	}
+
+	for i, ship := range layout.Ships {
+		for _, p := range ship.Cells() {
+			if p.X < 0 || p.X >= layout.Height || p.Y < 0 || p.Y >= layout.Width {
+				return "", ErrOutOfBoard
+			}
+			rows[p.X][p.Y] = layoutLetters[i]
+		}
+	}
+
+	var builder strings.Builder
+	for _, row := range rows {
+		builder.Write(row)
+		builder.WriteByte('\n')
+	}
+
+	return builder.String(), nil
+}
+
+//Returns layout as indented JSON
+func (layout Layout) JSON() (string, error) {
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+//Parses layout written by Layout.ASCII or Layout.JSON. Format is detected by the first character
+func ParseLayout(s string) (Layout, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Layout{}, errors.New("layout is empty")
+	}
+
+	if s[0] == '{' {
+		var layout Layout
+		if err := json.Unmarshal([]byte(s), &layout); err != nil {
+			return Layout{}, fmt.Errorf("parse layout: %w", err)
+		}
+		//ship can't be longer than side of the biggest board
+		for i, ship := range layout.Ships {
+			if err := ship.check(MaxBoardSize); err != nil {
+				return Layout{}, fmt.Errorf("ship %d of layout: %w", i+1, err)
+			}
+		}
+		return layout, nil
+	}
+
+	return parseASCII(s)
+}
+
+//Parses grid of characters written by Layout.ASCII
+func parseASCII(s string) (Layout, error) {
+	lines := strings.Split(s, "\n")
+	layout := Layout{Height: len(lines)}
+	decks := make(map[byte]Shape)
+	var order []byte
+
+	for x, line := range lines {
+		line = strings.TrimSpace(line)
+		if x == 0 {
+			layout.Width = len(line)
+		} else if len(line) != layout.Width {
+			return Layout{}, fmt.Errorf("row %d of layout has %d cells instead of %d", x+1, len(line), layout.Width)
+		}
+
+		for y := 0; y < len(line); y++ {
+			c := line[y]
+			if c == layoutEmpty {
+				continue
+			}
+			if strings.IndexByte(layoutLetters, c) < 0 {
+				return Layout{}, fmt.Errorf("invalid character %q in row %d of layout", c, x+1)
+			}
+			if _, ok := decks[c]; !ok {
+				order = append(order, c)
+			}
+			decks[c] = append(decks[c], Point{x, y})
+		}
+	}
+
+	for _, c := range order {
+		cells := decks[c]
+		if !cells.IsConnected() {
+			return Layout{}, fmt.Errorf("decks of ship %q are not connected", c)
+		}
+		//decks are collected row by row, so the first one is base deck
+		layout.Ships = append(layout.Ships, NewShapedShip(cells, cells[0]))
+	}
+
+	return layout, nil
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLayoutRoundTrip(t *testing.T) {
+	for _, rules := range Presets {
+		board := NewBoard(rules)
+		if err := board.PlaceRandomly(rand.New(rand.NewSource(7))); err != nil {
+			t.Fatal(err)
+		}
+		layout := board.Layout()
+
+		formats := map[string]func() (string, error){"ascii": layout.ASCII, "json": layout.JSON}
+		for name, format := range formats {
+			t.Run(rules.Name+"/"+name, func(t *testing.T) {
+				text, err := format()
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed, err := ParseLayout(text)
+				if err != nil {
+					t.Fatalf("ParseLayout() = %v", err)
+				}
+
+				placed, err := PlaceLayout(rules, parsed)
+				if err != nil {
+					t.Fatalf("PlaceLayout() = %v", err)
+				}
+				for _, p := range board.Points() {
+					if placed.At(p) != board.At(p) {
+						t.Fatalf("cell %v is %v, want %v", p, placed.At(p), board.At(p))
+					}
+				}
+				if len(placed.Fleet.Array) != len(board.Fleet.Array) {
+					t.Errorf("got %d ships, want %d", len(placed.Fleet.Array), len(board.Fleet.Array))
+				}
+			})
+		}
+	}
+}
+
+func TestParseLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", "  \n"},
+		{"rows of different length", "A..\n..\n"},
+		{"invalid character", "A.#\n...\n"},
+		{"ship isn't connected", "A.A\n...\n"},
+		{"invalid json", "{\"Width\": "},
+		{"negative size", `{"Width": 10, "Height": 10, "Ships": [{"Size": -1, "BaseDeckPosition": {"X": 0, "Y": 0}}]}`},
+		{"zero size", `{"Width": 10, "Height": 10, "Ships": [{"Size": 0}]}`},
+		{"size longer than any board", `{"Width": 10, "Height": 10, "Ships": [{"Size": 1000000000000}]}`},
+		{"unknown orientation", `{"Width": 10, "Height": 10, "Ships": [{"Size": 3, "Orientation": 2}]}`},
+		{"shape isn't connected", `{"Width": 10, "Height": 10, "Ships": [{"Size": 2, "Shape": [{"X": 0, "Y": 0}, {"X": 0, "Y": 2}]}]}`},
+		{"shape doesn't match size", `{"Width": 10, "Height": 10, "Ships": [{"Size": 3, "Shape": [{"X": 0, "Y": 0}, {"X": 0, "Y": 1}]}]}`},
+		{"shape with the same deck twice", `{"Width": 10, "Height": 10, "Ships": [{"Size": 2, "Shape": [{"X": 0, "Y": 0}, {"X": 0, "Y": 0}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseLayout(tt.text); err == nil {
+				t.Errorf("ParseLayout(%q) returned no error", tt.text)
+			}
+		})
+	}
+}
+
+func TestPlaceLayoutChecksRules(t *testing.T) {
+	empty := strings.Repeat(strings.Repeat(".", 10)+"\n", 9)
+	tests := []struct {
+		name   string
+		layout string
+	}{
+		{"wrong size", "A.\n..\n"},
+		{"ships touch", "AB........\n" + empty},
+		{"too many ships of kind", "A.B.C.D.E.\n" + empty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := ParseLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("ParseLayout() = %v", err)
+			}
+			if _, err := PlaceLayout(Classic, layout); err == nil {
+				t.Errorf("PlaceLayout() accepted invalid layout")
+			}
+		})
+	}
+}
+
+func TestPlaceLayoutChecksShips(t *testing.T) {
+	tests := []struct {
+		name string
+		ship Ship
+	}{
+		{"negative size", Ship{Size: -1}},
+		{"zero size", Ship{}},
+		{"longer than the largest ship of rules", NewShip(5, Horizontal, Point{0, 0})},
+		{"unknown orientation", Ship{Size: 3, Orientation: 2}},
+		{"shape isn't connected", Ship{Size: 2, Shape: Shape{{0, 0}, {1, 1}}}},
+		{"shape doesn't match size", Ship{Size: 4, Shape: ShapeL[:3]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := Layout{Width: Classic.Width, Height: Classic.Height, Ships: []Ship{tt.ship}}
+			if _, err := PlaceLayout(Classic, layout); !errors.Is(err, ErrInvalidShip) {
+				t.Errorf("PlaceLayout() = %v, want %v", err, ErrInvalidShip)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Limits of custom board size
+const (
+	MinBoardSize = 5
+	MaxBoardSize = 26
+)
+
+//Adjacency tells which contacts between ships are allowed
+type Adjacency int
+
+const (
+	NoTouch         Adjacency = iota //ships can't touch each other, even by corners (Russian rules)
+	CornersAllowed                   //ships can touch by corners, but not by sides
+	TouchingAllowed                  //ships can touch by sides and corners (Hasbro rules)
+)
+
+//AdjacencyNames contains names of all adjacency rules, indexed by Adjacency
+var AdjacencyNames = [...]string{
+	NoTouch:         "no-touch",
+	CornersAllowed:  "corners-allowed",
+	TouchingAllowed: "touching-allowed",
+}
+
+//Returns "no-touch", "corners-allowed" or "touching-allowed"
+func (a Adjacency) String() string {
+	if a < 0 || int(a) >= len(AdjacencyNames) {
+		return fmt.Sprintf("Adjacency(%d)", int(a))
+	}
+
+	return AdjacencyNames[a]
+}
+
+//Converts name returned by String to Adjacency
+func ParseAdjacency(s string) (Adjacency, error) {
+	for a, name := range AdjacencyNames {
+		if name == s {
+			return Adjacency(a), nil
+		}
+	}
+
+	return NoTouch, fmt.Errorf("unknown adjacency %q", s)
+}
+
+//Adjacency is encoded by its name in JSON
+func (a Adjacency) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Adjacency) UnmarshalText(text []byte) error {
+	parsed, err := ParseAdjacency(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+
+	return nil
+}
+
+//Returns offsets of cells around ship's deck which can't contain deck of another ship
+func (a Adjacency) forbiddenOffsets() []Point {
+	sides := []Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+	switch a {
+	case TouchingAllowed:
+		return nil
+	case CornersAllowed:
+		return sides
+	}
+
+	return append(sides, Point{-1, -1}, Point{-1, 1}, Point{1, -1}, Point{1, 1})
+}
+
+//ShipSpec describes one type of ship in fleet
+type ShipSpec struct {
+	Name  string
+	Size  int
+	Count int   //amount of ships of this type
+	Shape Shape `json:",omitempty"` //offsets of decks of non-straight ship, nil for straight ship of Size decks
+}
+
+//Returns offsets of decks of ship of this type in initial orientation
+func (spec ShipSpec) Offsets() Shape {
+	if spec.Shape != nil {
+		return spec.Shape.normalize()
+	}
+
+	return Line(spec.Size)
+}
+
+//Returns canonical key of ship's shape. Ships of the same kind are interchangeable
+func (spec ShipSpec) Kind() string {
+	return spec.Offsets().Kind()
+}
+
+//Creates ship of this type with base deck in given point. Its shape is turned
+//clockwise 'turns' times and then mirrored if 'mirror' is true
+func (spec ShipSpec) NewShip(base Point, turns int, mirror bool) Ship {
+	return NewShapedShip(spec.Offsets().Transform(turns, mirror), base)
+}
+
+//SalvoPerShip is value of Rules.Salvo which gives player one shot for each surviving ship
+const SalvoPerShip = -1
+
+//Rules describe size of board, composition of fleet, allowed contacts between ships
+//and number of shots per turn
+type Rules struct {
+	Name      string
+	Width     int //number of columns, range of Point.Y
+	Height    int //number of rows, range of Point.X
+	Fleet     []ShipSpec
+	Adjacency Adjacency
+	//shots per turn in salvo variant, where turn passes after every salvo, or SalvoPerShip.
+	//Zero means classic game: player shoots once and keeps turn after hit
+	Salvo int `json:",omitempty"`
+}
+
+//Classic is the Russian rule set: 10x10 board with one four-deck,
+//two three-deck, three double-deck and four single-deck ships, which can't touch
+var Classic = Rules{
+	Name:   "Classic",
+	Width:  10,
+	Height: 10,
+	Fleet: []ShipSpec{
+		{Name: "Four-deck ship", Size: 4, Count: 1},
+		{Name: "Three-deck ship", Size: 3, Count: 2},
+		{Name: "Double-deck ship", Size: 2, Count: 3},
+		{Name: "Single-deck ship", Size: 1, Count: 4},
+	},
+	Adjacency: NoTouch,
+}
+
+//MiltonBradley is the rule set of Milton Bradley's Battleship game, where ships may touch
+var MiltonBradley = Rules{
+	Name:   "Milton Bradley",
+	Width:  10,
+	Height: 10,
+	Fleet: []ShipSpec{
+		{Name: "Carrier", Size: 5, Count: 1},
+		{Name: "Battleship", Size: 4, Count: 1},
+		{Name: "Cruiser", Size: 3, Count: 1},
+		{Name: "Submarine", Size: 3, Count: 1},
+		{Name: "Destroyer", Size: 2, Count: 1},
+	},
+	Adjacency: TouchingAllowed,
+}
+
+//ClassicSalvo is salvo variant of Classic rules: player fires one shot for each surviving ship
+var ClassicSalvo = Rules{
+	Name:      "Salvo",
+	Width:     Classic.Width,
+	Height:    Classic.Height,
+	Fleet:     Classic.Fleet,
+	Adjacency: Classic.Adjacency,
+	Salvo:     SalvoPerShip,
+}
+
+//Polyomino is rule set with L-shaped, T-shaped and square ships besides straight ones
+var Polyomino = Rules{
+	Name:   "Polyomino",
+	Width:  10,
+	Height: 10,
+	Fleet: []ShipSpec{
+		{Name: "L-shaped ship", Size: 4, Count: 1, Shape: ShapeL},
+		{Name: "T-shaped ship", Size: 4, Count: 1, Shape: ShapeT},
+		{Name: "Square ship", Size: 4, Count: 1, Shape: ShapeSquare},
+		{Name: "Three-deck ship", Size: 3, Count: 2},
+		{Name: "Double-deck ship", Size: 2, Count: 2},
+	},
+	Adjacency: NoTouch,
+}
+
+//Presets contains predefined rule sets, Classic goes first
+var Presets = []Rules{Classic, MiltonBradley, ClassicSalvo, Polyomino}
+
+//Returns predefined rule set by its name
+func PresetByName(name string) (Rules, bool) {
+	for _, rules := range Presets {
+		if strings.EqualFold(rules.Name, name) {
+			return rules, true
+		}
+	}
+
+	return Rules{}, false
+}
+
+//Creates custom rule set and checks that it is playable. Rule set is named by its parameters,
+//e.g. "Custom 12x10 5,4,3,3,2 touching-allowed salvo 3", so different custom rules can be told apart
+//in records, profiles and saved layouts. Default adjacency and single shot are omitted from name
+func CustomRules(width int, height int, fleet []ShipSpec, adjacency Adjacency, salvo int) (Rules, error) {
+	name := fmt.Sprintf("Custom %dx%d %s", width, height, FormatFleet(fleet))
+	if adjacency != NoTouch {
+		name += " " + adjacency.String()
+	}
+	switch {
+	case salvo == SalvoPerShip:
+		name += " salvo ships"
+	case salvo > 0:
+		name += fmt.Sprintf(" salvo %d", salvo)
+	}
+
+	rules := Rules{Name: name, Width: width, Height: height, Fleet: fleet, Adjacency: adjacency, Salvo: salvo}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+
+	return rules, nil
+}
+
+//Returns error if board size is out of limits or fleet is empty, contains ships
+//which don't fit board, or takes more than half of board
+func (rules Rules) Validate() error {
+	if rules.Width < MinBoardSize || rules.Width > MaxBoardSize ||
+		rules.Height < MinBoardSize || rules.Height > MaxBoardSize {
+		return fmt.Errorf("board size %dx%d is out of range %d..%d", rules.Width, rules.Height, MinBoardSize, MaxBoardSize)
+	}
+	if len(rules.Fleet) == 0 {
+		return errors.New("fleet is empty")
+	}
+	if rules.Adjacency < NoTouch || rules.Adjacency > TouchingAllowed {
+		return fmt.Errorf("invalid %v", rules.Adjacency)
+	}
+	if rules.Salvo < SalvoPerShip {
+		return fmt.Errorf("invalid salvo size %d", rules.Salvo)
+	}
+
+	for _, spec := range rules.Fleet {
+		if spec.Size < 1 || spec.Count < 1 {
+			return fmt.Errorf("invalid ship %q: size %d, count %d", spec.Name, spec.Size, spec.Count)
+		}
+		if spec.Shape != nil && (len(spec.Shape) != spec.Size || !spec.Shape.IsConnected()) {
+			return fmt.Errorf("invalid shape of ship %q: %v", spec.Name, spec.Shape)
+		}
+		if height, width := spec.Offsets().bounds(); (height > rules.Height || width > rules.Width) &&
+			(width > rules.Height || height > rules.Width) {
+			return fmt.Errorf("ship %q doesn't fit %dx%d board", spec.Name, rules.Width, rules.Height)
+		}
+	}
+
+	if 2*rules.TotalDecks() > rules.Width*rules.Height {
+		return fmt.Errorf("fleet of %d decks is too big for %dx%d board", rules.TotalDecks(), rules.Width, rules.Height)
+	}
+
+	return nil
+}
+
+//Returns true if players fire salvos and turn passes after every salvo
+func (rules Rules) IsSalvo() bool {
+	return rules.Salvo != 0
+}
+
+//Returns number of shots which owner of given fleet fires in one turn
+func (rules Rules) ShotsPerTurn(own Fleet) int {
+	switch rules.Salvo {
+	case 0:
+		return 1
+	case SalvoPerShip:
+		return own.AliveShips()
+	}
+
+	return rules.Salvo
+}
+
+//Parses salvo size: "1" or empty string is classic single shot,
+//"ships" is one shot per surviving ship, other numbers are fixed salvo size
+func ParseSalvo(s string) (int, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "", "1":
+		return 0, nil
+	case "ships":
+		return SalvoPerShip, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of shots per turn %q", s)
+	}
+
+	return n, nil
+}
+
+//Returns maximum amount of ships of each kind, see ShipSpec.Kind
+func (rules Rules) CountByKind() map[string]int {
+	counts := make(map[string]int, len(rules.Fleet))
+
+	for _, spec := range rules.Fleet {
+		counts[spec.Kind()] += spec.Count
+	}
+
+	return counts
+}
+
+//Returns true if fleet contains ships which are not straight
+func (rules Rules) HasShapes() bool {
+	for _, spec := range rules.Fleet {
+		if spec.Shape != nil && !spec.Shape.IsLine() {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Returns number of ships in complete fleet
+func (rules Rules) ShipCount() int {
+	count := 0
+
+	for _, spec := range rules.Fleet {
+		count += spec.Count
+	}
+
+	return count
+}
+
+//Returns error if ship doesn't describe ship of 1 to the largest ship size of rules decks,
+//see ErrInvalidShip. It must be called for ships from untrusted data before they are placed
+func (rules Rules) CheckShip(ship Ship) error {
+	largest := 0
+	for _, spec := range rules.Fleet {
+		if spec.Size > largest {
+			largest = spec.Size
+		}
+	}
+
+	return ship.check(largest)
+}
+
+//Returns number of decks in complete fleet
+func (rules Rules) TotalDecks() int {
+	decks := 0
+
+	for _, spec := range rules.Fleet {
+		decks += spec.Size * spec.Count
+	}
+
+	return decks
+}
+
+//Parses fleet given as comma separated ship sizes, e.g. "5,4,3,3,2". Names of ShapeNames
+//may be used instead of sizes for ships of other shapes, e.g. "L,T,3,2".
+//Ships of the same size or shape are joined into one ShipSpec
+func ParseFleet(s string) ([]ShipSpec, error) {
+	specs := make(map[string]*ShipSpec)
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToUpper(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+
+		if spec, ok := specs[field]; ok {
+			spec.Count++
+			continue
+		}
+
+		if shape, ok := ShapeNames[field]; ok {
+			specs[field] = &ShipSpec{Name: field + "-shaped ship", Size: len(shape), Count: 1, Shape: shape}
+			continue
+		}
+
+		size, err := strconv.Atoi(field)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid ship size %q", field)
+		}
+		specs[field] = &ShipSpec{Name: fmt.Sprintf("%d-deck ship", size), Size: size, Count: 1}
+	}
+
+	var fleet []ShipSpec
+	for _, spec := range specs {
+		fleet = append(fleet, *spec)
+	}
+	sort.Slice(fleet, func(i, j int) bool {
+		if fleet[i].Size != fleet[j].Size {
+			return fleet[i].Size > fleet[j].Size
+		}
+		return fleet[i].Name < fleet[j].Name
+	})
+
+	return fleet, nil
+}
+
+//Formats fleet as comma separated ship sizes, which ParseFleet reads back. Ships of predefined
+//shapes are written by names of ShapeNames, e.g. "L,T,3,2"
+func FormatFleet(fleet []ShipSpec) string {
+	var fields []string
+
+	for _, spec := range fleet {
+		field := strconv.Itoa(spec.Size)
+		if spec.Shape != nil {
+			for name, shape := range ShapeNames {
+				if shape.Kind() == spec.Kind() {
+					field = name
+				}
+			}
+		}
+		for i := 0; i < spec.Count; i++ {
+			fields = append(fields, field)
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
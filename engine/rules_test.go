@@ -0,0 +1,61 @@
+package engine
+
+import "testing"
+
+func TestCustomRulesName(t *testing.T) {
+	tests := []struct {
+		fleet     string
+		width     int
+		height    int
+		adjacency Adjacency
+		salvo     int
+		want      string
+	}{
+		{"5,4,3,3,2", 12, 10, NoTouch, 0, "Custom 12x10 5,4,3,3,2"},
+		{"2,3,3,4,5", 12, 10, NoTouch, 0, "Custom 12x10 5,4,3,3,2"},
+		{"5,4,3,3,2", 10, 12, NoTouch, 0, "Custom 10x12 5,4,3,3,2"},
+		{"L,T,O,3,2", 10, 10, TouchingAllowed, 0, "Custom 10x10 L,O,T,3,2 touching-allowed"},
+		{"4,3,3", 8, 8, CornersAllowed, SalvoPerShip, "Custom 8x8 4,3,3 corners-allowed salvo ships"},
+		{"4,3,3", 8, 8, NoTouch, 3, "Custom 8x8 4,3,3 salvo 3"},
+	}
+
+	for _, tt := range tests {
+		fleet, err := ParseFleet(tt.fleet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules, err := CustomRules(tt.width, tt.height, fleet, tt.adjacency, tt.salvo)
+		if err != nil {
+			t.Fatalf("CustomRules(%q) = %v", tt.fleet, err)
+		}
+		if rules.Name != tt.want {
+			t.Errorf("CustomRules(%q) is named %q, want %q", tt.fleet, rules.Name, tt.want)
+		}
+		if _, ok := PresetByName(rules.Name); ok {
+			t.Errorf("custom rules are named as preset %q", rules.Name)
+		}
+	}
+}
+
+func TestFormatFleetRoundTrip(t *testing.T) {
+	for _, rules := range Presets {
+		t.Run(rules.Name, func(t *testing.T) {
+			text := FormatFleet(rules.Fleet)
+			fleet, err := ParseFleet(text)
+			if err != nil {
+				t.Fatalf("ParseFleet(%q) = %v", text, err)
+			}
+
+			parsed := Rules{Fleet: fleet}
+			want, got := rules.CountByKind(), parsed.CountByKind()
+			if len(got) != len(want) {
+				t.Fatalf("fleet %q has %d kinds of ships, want %d", text, len(got), len(want))
+			}
+			for kind, count := range want {
+				if got[kind] != count {
+					t.Errorf("fleet %q has %d ships of kind %s, want %d", text, got[kind], kind, count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Shape is a set of offsets of ship's decks from its base deck. Base deck is the first deck
+//of the top row, so it has offset (0, 0) and other decks are below it or to the right of it
+type Shape []Point
+
+//Predefined shapes of four decks
+var (
+	ShapeL      = Shape{{0, 0}, {1, 0}, {2, 0}, {2, 1}}
+	ShapeT      = Shape{{0, 0}, {0, 1}, {0, 2}, {1, 1}}
+	ShapeSquare = Shape{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+)
+
+//ShapeNames contains predefined shapes by the names used in fleet specs
+var ShapeNames = map[string]Shape{
+	"L": ShapeL,
+	"T": ShapeT,
+	"O": ShapeSquare,
+}
+
+//Returns shape of straight horizontal ship of given size
+func Line(size int) Shape {
+	shape := make(Shape, 0, size)
+	for i := 0; i < size; i++ {
+		shape = append(shape, Point{0, i})
+	}
+
+	return shape
+}
+
+//Returns shape turned clockwise by a quarter
+func (shape Shape) Rotate() Shape {
+	rotated := make(Shape, 0, len(shape))
+	for _, p := range shape {
+		rotated = append(rotated, Point{p.Y, -p.X})
+	}
+
+	return rotated.normalize()
+}
+
+//Returns shape mirrored from left to right
+func (shape Shape) Mirror() Shape {
+	mirrored := make(Shape, 0, len(shape))
+	for _, p := range shape {
+		mirrored = append(mirrored, Point{p.X, -p.Y})
+	}
+
+	return mirrored.normalize()
+}
+
+//Returns shape turned clockwise 'turns' times and then mirrored if 'mirror' is true
+func (shape Shape) Transform(turns int, mirror bool) Shape {
+	result := shape.normalize()
+	for i := 0; i < (turns%4+4)%4; i++ {
+		result = result.Rotate()
+	}
+	if mirror {
+		result = result.Mirror()
+	}
+
+	return result
+}
+
+//Returns all different shapes which can be made from shape by rotation and mirroring
+func (shape Shape) Variants() []Shape {
+	var variants []Shape
+	seen := make(map[string]bool, 8)
+
+	for _, mirror := range []bool{false, true} {
+		for turns := 0; turns < 4; turns++ {
+			variant := shape.Transform(turns, mirror)
+			if key := variant.String(); !seen[key] {
+				seen[key] = true
+				variants = append(variants, variant)
+			}
+		}
+	}
+
+	return variants
+}
+
+//Returns true if shape is a straight line
+func (shape Shape) IsLine() bool {
+	normalized := shape.normalize()
+	return normalized.String() == Line(len(shape)).String() ||
+		normalized.String() == Line(len(shape)).Rotate().String()
+}
+
+//Returns true if every deck of shape touches another one by side
+func (shape Shape) IsConnected() bool {
+	if len(shape) == 0 {
+		return false
+	}
+
+	cells := make(map[Point]bool, len(shape))
+	for _, p := range shape {
+		cells[p] = true
+	}
+
+	visited := map[Point]bool{shape[0]: true}
+	queue := []Point{shape[0]}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		for _, d := range sideOffsets {
+			n := Point{p.X + d.X, p.Y + d.Y}
+			if cells[n] && !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return len(visited) == len(cells)
+}
+
+//Returns canonical key of shape which is the same for all its rotations and mirrors
+func (shape Shape) Kind() string {
+	kind := ""
+	for _, variant := range shape.Variants() {
+		if key := variant.String(); kind == "" || key < kind {
+			kind = key
+		}
+	}
+
+	return kind
+}
+
+//Returns offsets as "x:y" pairs separated by spaces, e.g. "0:0 1:0 2:0 2:1"
+func (shape Shape) String() string {
+	parts := make([]string, 0, len(shape))
+	for _, p := range shape {
+		parts = append(parts, fmt.Sprintf("%d:%d", p.X, p.Y))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+//Sorts decks by rows and moves shape so its base deck has offset (0, 0)
+func (shape Shape) normalize() Shape {
+	if len(shape) == 0 {
+		return nil
+	}
+
+	sorted := append(Shape(nil), shape...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+
+	base := sorted[0]
+	for i := range sorted {
+		sorted[i] = Point{sorted[i].X - base.X, sorted[i].Y - base.Y}
+	}
+
+	return sorted
+}
+
+//Returns height and width of rectangle which contains shape
+func (shape Shape) bounds() (int, int) {
+	minX, maxX, minY, maxY := 0, 0, 0, 0
+	for _, p := range shape {
+		if p.X < minX {
+			minX = p.X
+		}
+		if p.X > maxX {
+			maxX = p.X
+		}
+		if p.Y < minY {
+			minY = p.Y
+		}
+		if p.Y > maxY {
+			maxY = p.Y
+		}
+	}
+
+	return maxX - minX + 1, maxY - minY + 1
+}
+
+//Offsets of four cells which touch a cell by side
+var sideOffsets = []Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
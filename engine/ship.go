@@ -0,0 +1,213 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+//ErrInvalidShip is returned when ship data, e.g. of imported layout or game record, doesn't describe a ship
+var ErrInvalidShip = errors.New("invalid ship")
+
+//Orientation shows in which direction ship's decks go from its base deck
+type Orientation int
+
+const (
+	Horizontal Orientation = iota //decks go to the right of base deck
+	Vertical                      //decks go down from base deck
+)
+
+//Returns "Horizontal" or "Vertical"
+func (o Orientation) String() string {
+	if o == Vertical {
+		return "Vertical"
+	}
+
+	return "Horizontal"
+}
+
+//Point represents coordinates of one cell. X is a row and Y is a column of board
+type Point struct {
+	X int
+	Y int
+}
+
+//Ship represents one ship placed on board. Straight ship is described by its size and orientation,
+//ship of other shape has offsets of its decks, already rotated and mirrored, in Shape
+type Ship struct {
+	Size             int
+	Orientation      Orientation //orientation of straight ship, it is Horizontal for other shapes
+	BaseDeckPosition Point       //position of top/left deck of ship
+	DecksAlive       int
+	Shape            Shape `json:",omitempty"` //nil for straight ship
+}
+
+//Creates new ship with all decks alive
+func NewShip(size int, orientation Orientation, base Point) Ship {
+	return Ship{
+		Size:             size,
+		Orientation:      orientation,
+		BaseDeckPosition: base,
+		DecksAlive:       size,
+	}
+}
+
+//Creates new ship of given shape with all decks alive. Straight shapes
+//are converted to straight ships, so they are described by orientation
+func NewShapedShip(shape Shape, base Point) Ship {
+	shape = shape.normalize()
+	if shape.IsLine() {
+		orientation := Horizontal
+		if len(shape) > 1 && shape[1].X == 1 {
+			orientation = Vertical
+		}
+		return NewShip(len(shape), orientation, base)
+	}
+
+	ship := NewShip(len(shape), Horizontal, base)
+	ship.Shape = shape
+
+	return ship
+}
+
+//Returns error if ship data, e.g. imported from file, doesn't describe ship of 1..maxSize decks:
+//straight ship must have known orientation, decks of other shape must be different and connected.
+//Offsets and Cells may be called only for ships which pass this check
+func (ship Ship) check(maxSize int) error {
+	if ship.Size < 1 || ship.Size > maxSize {
+		return fmt.Errorf("%w: size %d is out of range 1..%d", ErrInvalidShip, ship.Size, maxSize)
+	}
+	if ship.Shape == nil {
+		if ship.Orientation != Horizontal && ship.Orientation != Vertical {
+			return fmt.Errorf("%w: unknown orientation %d", ErrInvalidShip, int(ship.Orientation))
+		}
+		return nil
+	}
+
+	decks := make(map[Point]bool, len(ship.Shape))
+	for _, p := range ship.Shape {
+		decks[p] = true
+	}
+	if len(ship.Shape) != ship.Size || len(decks) != ship.Size {
+		return fmt.Errorf("%w: shape %v doesn't have %d different decks", ErrInvalidShip, ship.Shape, ship.Size)
+	}
+	if !ship.Shape.IsConnected() {
+		return fmt.Errorf("%w: decks of shape %v are not connected", ErrInvalidShip, ship.Shape)
+	}
+
+	return nil
+}
+
+//Returns positions of all decks of ship, starting from base deck
+func (ship Ship) Cells() []Point {
+	cells := make([]Point, 0, ship.Size)
+
+	if ship.Shape != nil {
+		for _, offset := range ship.Shape {
+			cells = append(cells, Point{ship.BaseDeckPosition.X + offset.X, ship.BaseDeckPosition.Y + offset.Y})
+		}
+		return cells
+	}
+
+	for i := 0; i < ship.Size; i++ {
+		switch ship.Orientation {
+		case Vertical:
+			cells = append(cells, Point{ship.BaseDeckPosition.X + i, ship.BaseDeckPosition.Y})
+		case Horizontal:
+			cells = append(cells, Point{ship.BaseDeckPosition.X, ship.BaseDeckPosition.Y + i})
+		}
+	}
+
+	return cells
+}
+
+//Returns offsets of ship's decks from its base deck
+func (ship Ship) Offsets() Shape {
+	if ship.Shape != nil {
+		return ship.Shape
+	}
+
+	return Line(ship.Size).Transform(int(ship.Orientation), false)
+}
+
+//Returns canonical key of ship's shape, which doesn't depend on rotation and mirroring
+func (ship Ship) Kind() string {
+	return ship.Offsets().Kind()
+}
+
+//Returns the same ship with base deck located in given point
+func (ship Ship) MoveTo(base Point) Ship {
+	return NewShapedShip(ship.Offsets(), base)
+}
+
+//Returns ship turned clockwise by a quarter around its base deck
+func (ship Ship) Rotate() Ship {
+	return NewShapedShip(ship.Offsets().Rotate(), ship.BaseDeckPosition)
+}
+
+//Returns true if one of ship's decks is located in given point
+func (ship Ship) Occupies(p Point) bool {
+	for _, cell := range ship.Cells() {
+		if cell == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Returns true if ship killed and false if ship still alive
+func (ship Ship) IsKilled() bool {
+	return ship.DecksAlive == 0
+}
+
+//Fleet contains all ships placed on board
+type Fleet struct {
+	TotalDecks int
+	Size       map[int]int //number of placed ships by their size
+	Array      []Ship
+}
+
+//Creates new empty fleet
+func NewFleet() Fleet {
+	return Fleet{Size: make(map[int]int)}
+}
+
+//Returns number of ships which are not killed
+func (fleet Fleet) AliveShips() int {
+	alive := 0
+
+	for _, ship := range fleet.Array {
+		if !ship.IsKilled() {
+			alive++
+		}
+	}
+
+	return alive
+}
+
+//Returns index of ship which occupies given point, or -1 if there is no such ship
+func (fleet Fleet) indexAt(p Point) int {
+	for i, ship := range fleet.Array {
+		if ship.Occupies(p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (fleet *Fleet) add(ship Ship) {
+	fleet.Size[ship.Size]++
+	fleet.TotalDecks += ship.DecksAlive
+	fleet.Array = append(fleet.Array, ship)
+}
+
+func (fleet *Fleet) remove(i int) Ship {
+	ship := fleet.Array[i]
+
+	fleet.Size[ship.Size]--
+	fleet.TotalDecks -= ship.DecksAlive
+	fleet.Array = append(fleet.Array[:i:i], fleet.Array[i+1:]...)
+
+	return ship
+}
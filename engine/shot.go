@@ -0,0 +1,260 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//ShotResult represents result of one shot
+type ShotResult int
+
+const (
+	ShotMiss ShotResult = iota
+	ShotHit
+	ShotKill
+)
+
+var ErrAlreadyShot = errors.New("cell was shot already")
+
+var shotResultNames = [...]string{
+	ShotMiss: "miss",
+	ShotHit:  "hit",
+	ShotKill: "kill",
+}
+
+//Returns "miss", "hit" or "kill". The same values are used by server
+func (result ShotResult) String() string {
+	if result < 0 || int(result) >= len(shotResultNames) {
+		return fmt.Sprintf("ShotResult(%d)", int(result))
+	}
+
+	return shotResultNames[result]
+}
+
+//Converts "miss", "hit" or "kill" received from server to ShotResult
+func ParseShotResult(s string) (ShotResult, error) {
+	for result, name := range shotResultNames {
+		if name == s {
+			return ShotResult(result), nil
+		}
+	}
+
+	return ShotMiss, fmt.Errorf("unknown shot result %q", s)
+}
+
+//Applies opponent's shot to player's own board. Edits fleet and ship parameters
+//when opponent hit or kill ship, and covers cells around killed ship
+func (board *Board) Receive(p Point) (ShotResult, error) {
+	if !board.InBounds(p) {
+		return ShotMiss, ErrOutOfBoard
+	}
+
+	switch board.At(p) {
+	case CellMiss, CellHit:
+		return ShotMiss, ErrAlreadyShot
+	case CellEmpty:
+		board.Cells[p.X][p.Y] = CellMiss
+		return ShotMiss, nil
+	}
+
+	board.Cells[p.X][p.Y] = CellHit
+
+	i := board.Fleet.indexAt(p)
+	ship := &board.Fleet.Array[i]
+	ship.DecksAlive--
+	board.Fleet.TotalDecks--
+
+	if !ship.IsKilled() {
+		return ShotHit, nil
+	}
+
+	board.cover(ship.Cells())
+
+	return ShotKill, nil
+}
+
+//Returns result of opponent's shot which was already received in given point.
+//Returns false if the point was not shot yet
+func (board *Board) ResultAt(p Point) (ShotResult, bool) {
+	if !board.InBounds(p) {
+		return ShotMiss, false
+	}
+
+	switch board.At(p) {
+	case CellMiss:
+		return ShotMiss, true
+	case CellHit:
+		if ship, ok := board.ShipAt(p); ok && ship.IsKilled() {
+			return ShotKill, true
+		}
+		return ShotHit, true
+	}
+
+	return ShotMiss, false
+}
+
+//Records result of player's shot on opponent's board.
+//When ship is killed, it is added to board's fleet as known sunk ship
+//and cells around whole ship are marked as missed
+func (board *Board) Mark(p Point, result ShotResult) error {
+	if !board.InBounds(p) {
+		return ErrOutOfBoard
+	}
+
+	switch result {
+	case ShotMiss:
+		board.Cells[p.X][p.Y] = CellMiss
+	case ShotHit:
+		board.Cells[p.X][p.Y] = CellHit
+	case ShotKill:
+		board.Cells[p.X][p.Y] = CellHit
+
+		cells := board.KilledShipCells(p)
+		if board.Fleet.indexAt(p) < 0 {
+			sunk := shipFromCells(cells)
+			sunk.DecksAlive = 0
+			board.Fleet.add(sunk)
+		}
+		board.cover(cells)
+	}
+
+	return nil
+}
+
+//Returns all decks of killed ship, which last hit deck is located in given point. Ship is restored
+//from hit cells connected to the last hit deck, skipping decks of ships which are sunk already.
+//If ships may touch, these cells may belong to several ships, so the biggest ship of kinds still
+//afloat, which covers the last hit deck and only such cells, is taken. Straight ship prefers
+//placements where the last hit deck is its end
+func (board *Board) KilledShipCells(p Point) []Point {
+	decks := board.woundedDecks(p)
+
+	var best []Point
+	for _, shape := range board.afloatShapes() {
+		if len(shape) <= len(best) {
+			continue
+		}
+
+		for _, variant := range shape.Variants() {
+			//base deck is chosen so that the last hit deck is the first, the last and then other decks of ship
+			for _, i := range endsFirst(len(variant)) {
+				base := Point{p.X - variant[i].X, p.Y - variant[i].Y}
+				cells := NewShapedShip(variant, base).Cells()
+				if containsAll(decks, cells) && len(cells) > len(best) {
+					best = cells
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return decks
+	}
+
+	return best
+}
+
+//Returns given point and hit decks of ships, which are not sunk yet, connected to it by sides
+func (board *Board) woundedDecks(p Point) []Point {
+	cells := []Point{p}
+	visited := map[Point]bool{p: true}
+
+	for i := 0; i < len(cells); i++ {
+		for _, d := range sideOffsets {
+			n := Point{cells[i].X + d.X, cells[i].Y + d.Y}
+			if !board.InBounds(n) || visited[n] || !isDeck(board.At(n)) {
+				continue
+			}
+			visited[n] = true
+			if ship, ok := board.ShipAt(n); ok && ship.IsKilled() {
+				continue
+			}
+			cells = append(cells, n)
+		}
+	}
+
+	return cells
+}
+
+//Returns shapes of ship kinds of rules which are not sunk yet on board, the biggest first
+func (board *Board) afloatShapes() []Shape {
+	afloat := board.Rules.CountByKind()
+	for _, ship := range board.Fleet.Array {
+		if ship.IsKilled() {
+			afloat[ship.Kind()]--
+		}
+	}
+
+	var shapes []Shape
+	for _, spec := range board.Rules.Fleet {
+		kind := spec.Kind()
+		if afloat[kind] > 0 {
+			shapes = append(shapes, spec.Offsets())
+		}
+		delete(afloat, kind) //ships of the same kind may be described by several specs
+	}
+	sort.SliceStable(shapes, func(i, j int) bool { return len(shapes[i]) > len(shapes[j]) })
+
+	return shapes
+}
+
+//Returns indexes of n decks: the first, the last and then the others
+func endsFirst(n int) []int {
+	indexes := []int{0}
+	if n > 1 {
+		indexes = append(indexes, n-1)
+	}
+	for i := 1; i < n-1; i++ {
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
+
+//Returns true if every point of 'points' is one of 'set'
+func containsAll(set []Point, points []Point) bool {
+	for _, p := range points {
+		found := false
+		for _, s := range set {
+			if s == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Restores ship from positions of its decks
+func shipFromCells(cells []Point) Ship {
+	base := cells[0]
+
+	for _, c := range cells {
+		if c.X < base.X || (c.X == base.X && c.Y < base.Y) {
+			base = c
+		}
+	}
+
+	return NewShapedShip(Shape(cells), base)
+}
+
+//Marks empty cells around given decks, which can't contain another ship by adjacency rule, as missed.
+//Nothing is marked if ships may touch, because neighbouring cells may still contain decks
+func (board *Board) cover(decks []Point) {
+	for _, deck := range decks {
+		for _, n := range board.Neighbours(deck) {
+			if board.At(n) == CellEmpty {
+				board.Cells[n.X][n.Y] = CellMiss
+			}
+		}
+	}
+}
+
+func isDeck(state CellState) bool {
+	return state == CellHit || state == CellDeck
+}
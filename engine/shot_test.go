@@ -0,0 +1,257 @@
+package engine
+
+import (
+	"sort"
+	"testing"
+)
+
+//shot is result of player's shot used to build opponent's board in tests
+type shot struct {
+	p      Point
+	result ShotResult
+}
+
+//Returns opponent's board of given rules with shots marked in order
+func markedBoard(t *testing.T, rules Rules, shots []shot) *Board {
+	t.Helper()
+
+	board := NewBoard(rules)
+	for _, s := range shots {
+		if err := board.Mark(s.p, s.result); err != nil {
+			t.Fatalf("Mark(%v, %v): %v", s.p, s.result, err)
+		}
+	}
+
+	return board
+}
+
+//Returns points sorted row by row, so sets of points can be compared
+func sortedPoints(points []Point) []Point {
+	sorted := append([]Point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+
+	return sorted
+}
+
+func samePoints(a []Point, b []Point) bool {
+	a, b = sortedPoints(a), sortedPoints(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestMarkKillOfTouchingShips(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		shots []shot
+		//decks of sunk ships in order they were sunk
+		sunk [][]Point
+	}{
+		{
+			name:  "ship touching sunk ship in line",
+			rules: MiltonBradley,
+			shots: []shot{
+				{Point{0, 0}, ShotHit}, {Point{0, 1}, ShotKill},
+				{Point{0, 2}, ShotHit}, {Point{0, 3}, ShotHit}, {Point{0, 4}, ShotKill},
+			},
+			sunk: [][]Point{{{0, 0}, {0, 1}}, {{0, 2}, {0, 3}, {0, 4}}},
+		},
+		{
+			name:  "ship touching sunk ship by side",
+			rules: MiltonBradley,
+			shots: []shot{
+				{Point{0, 0}, ShotHit}, {Point{0, 1}, ShotHit}, {Point{0, 2}, ShotKill},
+				{Point{1, 0}, ShotHit}, {Point{1, 1}, ShotKill},
+			},
+			sunk: [][]Point{{{0, 0}, {0, 1}, {0, 2}}, {{1, 0}, {1, 1}}},
+		},
+		{
+			name:  "ship under sunk ship",
+			rules: MiltonBradley,
+			shots: []shot{
+				{Point{0, 0}, ShotHit}, {Point{0, 1}, ShotHit}, {Point{0, 2}, ShotKill},
+				{Point{1, 0}, ShotHit}, {Point{2, 0}, ShotKill},
+			},
+			sunk: [][]Point{{{0, 0}, {0, 1}, {0, 2}}, {{1, 0}, {2, 0}}},
+		},
+		{
+			name:  "kill next to wounded ship",
+			rules: MiltonBradley,
+			shots: []shot{
+				{Point{5, 5}, ShotHit}, {Point{5, 6}, ShotHit},
+				{Point{4, 5}, ShotHit}, {Point{3, 5}, ShotKill},
+			},
+			sunk: [][]Point{{{3, 5}, {4, 5}, {5, 5}}},
+		},
+		{
+			name:  "line longer than any ship afloat",
+			rules: MiltonBradley,
+			shots: []shot{
+				{Point{2, 0}, ShotHit}, {Point{2, 1}, ShotHit}, {Point{2, 2}, ShotHit},
+				{Point{2, 3}, ShotHit}, {Point{2, 4}, ShotHit}, {Point{2, 5}, ShotKill},
+			},
+			sunk: [][]Point{{{2, 1}, {2, 2}, {2, 3}, {2, 4}, {2, 5}}},
+		},
+		{
+			name:  "ships which can't touch",
+			rules: Classic,
+			shots: []shot{
+				{Point{0, 0}, ShotHit}, {Point{1, 0}, ShotHit}, {Point{2, 0}, ShotKill},
+			},
+			sunk: [][]Point{{{0, 0}, {1, 0}, {2, 0}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := markedBoard(t, tt.rules, tt.shots)
+
+			if len(board.Fleet.Array) != len(tt.sunk) {
+				t.Fatalf("got %d sunk ships, want %d", len(board.Fleet.Array), len(tt.sunk))
+			}
+			for i, ship := range board.Fleet.Array {
+				if !ship.IsKilled() {
+					t.Errorf("ship %d is not killed", i)
+				}
+				if !samePoints(ship.Cells(), tt.sunk[i]) {
+					t.Errorf("ship %d has decks %v, want %v", i, sortedPoints(ship.Cells()), tt.sunk[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKilledShipCellsCoversNeighbours(t *testing.T) {
+	board := markedBoard(t, Classic, []shot{{Point{4, 4}, ShotHit}, {Point{4, 5}, ShotKill}})
+
+	for _, p := range []Point{{3, 3}, {3, 4}, {3, 5}, {3, 6}, {4, 3}, {4, 6}, {5, 3}, {5, 4}, {5, 5}, {5, 6}} {
+		if board.At(p) != CellMiss {
+			t.Errorf("cell %v around killed ship is %v, want miss", p, board.At(p))
+		}
+	}
+	if board.At(Point{2, 4}) != CellEmpty {
+		t.Errorf("cell (2, 4) is covered, but it doesn't touch killed ship")
+	}
+}
+
+func TestReceive(t *testing.T) {
+	board := NewBoard(Classic)
+	for _, ship := range []Ship{NewShip(2, Horizontal, Point{0, 0}), NewShip(1, Horizontal, Point{5, 5})} {
+		if err := board.Place(ship); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		p    Point
+		want ShotResult
+		err  error
+	}{
+		{Point{9, 9}, ShotMiss, nil},
+		{Point{9, 9}, ShotMiss, ErrAlreadyShot},
+		{Point{0, 0}, ShotHit, nil},
+		{Point{0, 0}, ShotMiss, ErrAlreadyShot},
+		{Point{0, 1}, ShotKill, nil},
+		{Point{5, 5}, ShotKill, nil},
+		{Point{10, 0}, ShotMiss, ErrOutOfBoard},
+	}
+
+	for _, tt := range tests {
+		got, err := board.Receive(tt.p)
+		if got != tt.want || err != tt.err {
+			t.Errorf("Receive(%v) = %v, %v, want %v, %v", tt.p, got, err, tt.want, tt.err)
+		}
+	}
+
+	//cells around killed ships are covered
+	for _, p := range []Point{{1, 0}, {1, 1}, {1, 2}, {0, 2}, {4, 4}, {6, 6}} {
+		if board.At(p) != CellMiss {
+			t.Errorf("cell %v is %v, want miss", p, board.At(p))
+		}
+	}
+	if board.Fleet.TotalDecks != 0 || board.Fleet.AliveShips() != 0 {
+		t.Errorf("fleet has %d decks of %d ships alive, want none", board.Fleet.TotalDecks, board.Fleet.AliveShips())
+	}
+}
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		name  string
+		shots []shot
+		cells map[Point]CellState
+		sunk  int
+	}{
+		{"miss", []shot{{Point{1, 1}, ShotMiss}}, map[Point]CellState{{1, 1}: CellMiss, {1, 2}: CellEmpty}, 0},
+		{"hit", []shot{{Point{1, 1}, ShotHit}}, map[Point]CellState{{1, 1}: CellHit, {0, 0}: CellEmpty}, 0},
+		{
+			"kill covers ship",
+			[]shot{{Point{1, 1}, ShotHit}, {Point{1, 2}, ShotKill}},
+			map[Point]CellState{{1, 1}: CellHit, {1, 2}: CellHit, {0, 0}: CellMiss, {2, 3}: CellMiss, {1, 4}: CellEmpty},
+			1,
+		},
+		{"out of board", []shot{{Point{-1, 0}, ShotMiss}}, map[Point]CellState{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewBoard(Classic)
+			for _, s := range tt.shots {
+				err := board.Mark(s.p, s.result)
+				if board.InBounds(s.p) != (err == nil) {
+					t.Errorf("Mark(%v) = %v", s.p, err)
+				}
+			}
+
+			for p, want := range tt.cells {
+				if board.At(p) != want {
+					t.Errorf("cell %v is %v, want %v", p, board.At(p), want)
+				}
+			}
+			if len(board.Fleet.Array) != tt.sunk {
+				t.Errorf("got %d sunk ships, want %d", len(board.Fleet.Array), tt.sunk)
+			}
+		})
+	}
+}
+
+func TestKilledShipCells(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		hits  []Point
+		p     Point
+		want  []Point
+	}{
+		{"single deck", Classic, nil, Point{3, 3}, []Point{{3, 3}}},
+		{"horizontal", Classic, []Point{{3, 1}, {3, 2}}, Point{3, 3}, []Point{{3, 1}, {3, 2}, {3, 3}}},
+		{"vertical, last hit in the middle", Classic, []Point{{2, 3}, {4, 3}, {5, 3}}, Point{3, 3}, []Point{{2, 3}, {3, 3}, {4, 3}, {5, 3}}},
+		{"L-shaped", Polyomino, []Point{{0, 0}, {1, 0}, {2, 0}}, Point{2, 1}, []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		{"square", Polyomino, []Point{{0, 0}, {0, 1}, {1, 0}}, Point{1, 1}, []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewBoard(tt.rules)
+			for _, p := range append(tt.hits, tt.p) {
+				board.Mark(p, ShotHit)
+			}
+
+			if got := board.KilledShipCells(tt.p); !samePoints(got, tt.want) {
+				t.Errorf("KilledShipCells(%v) = %v, want %v", tt.p, sortedPoints(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"client.go/api"
+)
+
+//Delay before reconnecting to events stream after it was interrupted
+const reconnectDelay = 2 * time.Second
+
+//Receives states of remote game pushed by server until context is cancelled. Stream is reopened
+//when it is interrupted. If server doesn't support events, multiplayer room falls back to polling
+func watchGame(ctx context.Context, client *api.Client, gameID string, isRoom bool) {
+	for {
+		err := client.Subscribe(ctx, gameID, func(game api.GameData) {
+			gameMu.Lock()
+			defer gameMu.Unlock()
+
+			if ctx.Err() == nil {
+				applyGameState(ctx, game)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		if api.IsUnsupported(err) {
+			fmt.Println(err)
+			if isRoom {
+				pollRoom(ctx, client, gameID)
+			}
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+//Applies state of game received from server. FleetProtocol server resolves opponent's shots itself,
+//because it keeps fleets of both players. In rooms of LegacyProtocol server opponent's shot is resolved
+//on user's field and reported back. The same state can be applied several times
+func applyGameState(ctx context.Context, game api.GameData) {
+	if game.GameID != gameData.GameID {
+		return
+	}
+
+	gameData = game
+	analyzeResponse()
+	applyBotShots(gameData.BotShots)
+	if isRoom && gameData.Turn == api.TurnBot {
+		reportOpponentShot(ctx)
+	}
+
+	updateStatus()
+	autosave()
+	checkGameOver()
+}
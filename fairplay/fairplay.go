@@ -0,0 +1,139 @@
+//Package fairplay implements commit-reveal verification of fleets. At game start each player
+//publishes salted hash of its fleet; at game end it reveals fleet and salt, so opponent can
+//check that fleet was not changed and every shot was answered honestly
+package fairplay
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"client.go/engine"
+)
+
+//SaltSize is the number of random bytes in salt
+const SaltSize = 32
+
+//ErrCommitmentMismatch is returned when revealed fleet and salt don't match commitment
+var ErrCommitmentMismatch = errors.New("revealed fleet doesn't match commitment")
+
+//Shot represents recorded shot and result which was reported for it
+type Shot struct {
+	X      int
+	Y      int
+	Result engine.ShotResult
+}
+
+//Mismatch represents shot which was answered with wrong result
+type Mismatch struct {
+	Shot     Shot
+	Expected engine.ShotResult //result according to revealed fleet
+}
+
+//CheatingError is returned when revealed fleet is valid, but some shots were answered dishonestly
+type CheatingError struct {
+	Mismatches []Mismatch
+}
+
+func (e *CheatingError) Error() string {
+	descriptions := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		descriptions = append(descriptions, fmt.Sprintf("(%d, %d) reported as %s, should be %s",
+			m.Shot.X, m.Shot.Y, m.Shot.Result, m.Expected))
+	}
+
+	return "cheating detected: " + strings.Join(descriptions, "; ")
+}
+
+//Returns new random salt
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+//Serializes fleet to canonical form: one "size,orientation,x,y" line per ship, sorted by position.
+//Ships which are not straight have offsets of their decks appended as ",x:y x:y ...".
+//Number of alive decks is not included
+func Serialize(ships []engine.Ship) []byte {
+	sorted := append([]engine.Ship(nil), ships...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].BaseDeckPosition, sorted[j].BaseDeckPosition
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.Y < b.Y
+	})
+
+	var buffer bytes.Buffer
+	for _, ship := range sorted {
+		fmt.Fprintf(&buffer, "%d,%s,%d,%d", ship.Size, ship.Orientation, ship.BaseDeckPosition.X, ship.BaseDeckPosition.Y)
+		if ship.Shape != nil {
+			fmt.Fprintf(&buffer, ",%s", ship.Shape)
+		}
+		buffer.WriteString("\n")
+	}
+
+	return buffer.Bytes()
+}
+
+//Returns hex encoded SHA-256 hash of salt followed by serialized fleet
+func Commit(ships []engine.Ship, salt []byte) string {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write(Serialize(ships))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+//Returns true if fleet and salt match commitment
+func Verify(commitment string, ships []engine.Ship, salt []byte) bool {
+	return subtle.ConstantTimeCompare([]byte(commitment), []byte(Commit(ships, salt))) == 1
+}
+
+//Checks that revealed fleet matches commitment, is placed by the rules
+//and that every recorded shot was answered according to it
+func Audit(commitment string, rules engine.Rules, ships []engine.Ship, salt []byte, shots []Shot) error {
+	if !Verify(commitment, ships, salt) {
+		return ErrCommitmentMismatch
+	}
+
+	board := engine.NewBoard(rules)
+	for _, ship := range ships {
+		if err := board.Place(engine.NewShapedShip(ship.Offsets(), ship.BaseDeckPosition)); err != nil {
+			return fmt.Errorf("revealed fleet is invalid: %w", err)
+		}
+	}
+	if !board.IsComplete() {
+		return errors.New("revealed fleet is incomplete")
+	}
+
+	var mismatches []Mismatch
+	for _, shot := range shots {
+		expected, err := board.Receive(engine.Point{X: shot.X, Y: shot.Y})
+		if errors.Is(err, engine.ErrAlreadyShot) {
+			expected, _ = board.ResultAt(engine.Point{X: shot.X, Y: shot.Y})
+		} else if err != nil {
+			return fmt.Errorf("shot (%d, %d): %w", shot.X, shot.Y, err)
+		}
+
+		if expected != shot.Result {
+			mismatches = append(mismatches, Mismatch{Shot: shot, Expected: expected})
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &CheatingError{Mismatches: mismatches}
+	}
+
+	return nil
+}
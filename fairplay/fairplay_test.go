@@ -0,0 +1,156 @@
+package fairplay
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"client.go/engine"
+)
+
+//Returns complete fleet of classic rules placed with fixed seed
+func testFleet(t *testing.T) []engine.Ship {
+	t.Helper()
+
+	board := engine.NewBoard(engine.Classic)
+	if err := board.PlaceRandomly(rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+
+	return board.Fleet.Array
+}
+
+//Returns shots at every cell in given rows with results answered honestly by given fleet
+func honestShots(t *testing.T, ships []engine.Ship, rows int) []Shot {
+	t.Helper()
+
+	board := engine.NewBoard(engine.Classic)
+	for _, ship := range ships {
+		if err := board.Place(ship); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var shots []Shot
+	for x := 0; x < rows; x++ {
+		for y := 0; y < engine.Classic.Width; y++ {
+			result, err := board.Receive(engine.Point{X: x, Y: y})
+			if errors.Is(err, engine.ErrAlreadyShot) {
+				continue
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			shots = append(shots, Shot{X: x, Y: y, Result: result})
+		}
+	}
+
+	return shots
+}
+
+func TestVerify(t *testing.T) {
+	ships := testFleet(t)
+	salt, err := NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := Commit(ships, salt)
+
+	reversed := make([]engine.Ship, len(ships))
+	for i, ship := range ships {
+		reversed[len(ships)-1-i] = ship
+	}
+	moved := append([]engine.Ship(nil), ships...)
+	moved[0] = moved[0].MoveTo(engine.Point{X: moved[0].BaseDeckPosition.X + 1, Y: moved[0].BaseDeckPosition.Y})
+	otherSalt := append([]byte(nil), salt...)
+	otherSalt[0] ^= 1
+
+	tests := []struct {
+		name  string
+		ships []engine.Ship
+		salt  []byte
+		want  bool
+	}{
+		{"same fleet", ships, salt, true},
+		{"ships in other order", reversed, salt, true},
+		{"moved ship", moved, salt, false},
+		{"other salt", ships, otherSalt, false},
+		{"no ships", nil, salt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(commitment, tt.ships, tt.salt); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	ships := testFleet(t)
+	salt := make([]byte, SaltSize)
+	commitment := Commit(ships, salt)
+	shots := honestShots(t, ships, 4)
+
+	//every shot at a deck is reported as miss
+	lies := append([]Shot(nil), shots...)
+	var hidden []Shot
+	for i, shot := range lies {
+		if shot.Result != engine.ShotMiss {
+			hidden = append(hidden, shot)
+			lies[i].Result = engine.ShotMiss
+		}
+	}
+	if len(hidden) == 0 {
+		t.Fatal("test shots don't hit any ship")
+	}
+
+	tests := []struct {
+		name       string
+		commitment string
+		ships      []engine.Ship
+		shots      []Shot
+		mismatches int //expected number of mismatches in CheatingError
+		err        error
+	}{
+		{"honest game", commitment, ships, shots, 0, nil},
+		{"no shots", commitment, ships, nil, 0, nil},
+		{"hits reported as misses", commitment, ships, lies, len(hidden), nil},
+		{"fleet changed after commitment", Commit(ships[1:], salt), ships, shots, 0, ErrCommitmentMismatch},
+		{"incomplete fleet", Commit(ships[1:], salt), ships[1:], nil, 0, errors.New("revealed fleet is incomplete")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Audit(tt.commitment, engine.Classic, tt.ships, salt, tt.shots)
+
+			var cheating *CheatingError
+			switch {
+			case tt.mismatches > 0:
+				if !errors.As(err, &cheating) {
+					t.Fatalf("Audit() = %v, want cheating error", err)
+				}
+				if len(cheating.Mismatches) != tt.mismatches {
+					t.Errorf("got %d mismatches, want %d", len(cheating.Mismatches), tt.mismatches)
+				}
+				for i, m := range cheating.Mismatches {
+					if m.Expected != hidden[i].Result {
+						t.Errorf("shot (%d, %d) expected %v, want %v", m.Shot.X, m.Shot.Y, m.Expected, hidden[i].Result)
+					}
+				}
+			case tt.err == nil:
+				if err != nil {
+					t.Errorf("Audit() = %v, want nil", err)
+				}
+			case errors.Is(tt.err, ErrCommitmentMismatch):
+				if !errors.Is(err, ErrCommitmentMismatch) {
+					t.Errorf("Audit() = %v, want %v", err, tt.err)
+				}
+			default:
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Errorf("Audit() = %v, want %v", err, tt.err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"client.go/api"
+	"client.go/engine"
+	"client.go/local"
+	"client.go/record"
+	"client.go/strategy"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+var gameFinished bool //true when fleet of user or opponent is destroyed, shooting is locked then
+
+//Returns true if fleet of user or opponent is destroyed
+func gameOver() bool {
+	return botBoard.FleetDestroyed() || userBoard.FleetDestroyed()
+}
+
+//Finishes game when fleet of one of players is destroyed: fleets are verified, record of game
+//is stored, result is added to user's profile and results are shown. It is called after every change of game state, so it does
+//nothing until game is over and does nothing after game is finished
+func checkGameOver() {
+	if gameFinished || !gameOver() {
+		return
+	}
+	gameFinished = true
+
+	if stopGame != nil {
+		stopGame()
+		stopGame = nil
+	}
+	verifyFleets()
+	finishRecord()
+	updateProfile()
+	removeSave()
+	showResults(mainWindow)
+}
+
+//Closes current game on backend and clears its state. Game which is not finished yet
+//is verified and recorded first
+func leaveGame() {
+	if stopGame != nil {
+		stopGame()
+		stopGame = nil
+	}
+	if !gameFinished {
+		verifyFleets()
+		finishRecord()
+	}
+	if err := backend.EndGame(context.Background(), gameData.GameID); err != nil {
+		showError(err)
+	}
+	removeSave()
+
+	rules := userBoard.Rules
+	gameData = api.GameData{}
+	userBoard = engine.NewBoard(rules)
+	botBoard = engine.NewBoard(rules)
+	userCellArray = nil
+	botCellArray = nil
+	heatmapContainer = nil
+}
+
+//Starts new game against the same opponent with the same user's fleet and new seed.
+//Offline bot plays with the same strategy
+func rematch(window fyne.Window) {
+	username, _ := playerNames()
+	rules := userBoard.Rules
+	fleet, err := engine.PlaceLayout(rules, engine.Layout{Width: rules.Width, Height: rules.Height, Ships: gameRecord.UserFleet})
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	b := backend
+	seed := time.Now().UnixNano()
+	if _, ok := b.(*local.Server); ok {
+		bot, err := strategy.New(offlineStrategy, seededRand(seed, local.BotShots))
+		if err != nil {
+			showError(err)
+			return
+		}
+		b = local.NewServer(bot)
+	}
+
+	leaveGame()
+	userBoard = fleet
+	startGame(window, b, username, seed)
+}
+
+//Shows winner and statistics of both players. User can start rematch, which isn't
+//available in multiplayer rooms, or return to main container to start new game
+func showResults(window fyne.Window) {
+	username, opponent := playerNames()
+	if opponent == "" {
+		opponent = "Opponent"
+	}
+	title := "You won"
+	if userBoard.FleetDestroyed() {
+		title = opponent + " won"
+	}
+
+	userStats := gameRecord.Stats(record.User)
+	opponentStats := gameRecord.Stats(record.Opponent)
+	table := container.NewGridWithColumns(3,
+		widget.NewLabel(""),
+		widget.NewLabelWithStyle(username, fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(opponent, fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+	)
+	rows := []struct {
+		name  string
+		value func(record.Stats) string
+	}{
+		{"Shots fired", func(s record.Stats) string { return fmt.Sprint(s.Shots) }},
+		{"Hits", func(s record.Stats) string { return fmt.Sprint(s.Hits) }},
+		{"Ships sunk", func(s record.Stats) string { return fmt.Sprint(s.Kills) }},
+		{"Accuracy", func(s record.Stats) string { return fmt.Sprintf("%.0f%%", 100*s.Accuracy) }},
+		{"Longest hit streak", func(s record.Stats) string { return fmt.Sprint(s.LongestStreak) }},
+	}
+	for _, row := range rows {
+		table.Add(widget.NewLabel(row.name))
+		table.Add(widget.NewLabelWithStyle(row.value(userStats), fyne.TextAlignTrailing, fyne.TextStyle{}))
+		table.Add(widget.NewLabelWithStyle(row.value(opponentStats), fyne.TextAlignTrailing, fyne.TextStyle{}))
+	}
+
+	var results dialog.Dialog
+	rematchButton := widget.NewButton("Rematch", func() {
+		results.Hide()
+		rematch(window)
+	})
+	if isRoom {
+		rematchButton.Disable()
+	}
+	newGameButton := widget.NewButton("New game", func() {
+		results.Hide()
+		leaveGame()
+		newMainContainer(window)
+	})
+
+	content := container.NewVBox(
+		table,
+		widget.NewLabel("Time played: "+gameRecord.Duration().Round(time.Second).String()),
+		container.NewGridWithColumns(2, rematchButton, newGameButton),
+	)
+	results = dialog.NewCustom(title, "Close", content, window)
+	results.Show()
+}
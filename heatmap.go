@@ -0,0 +1,55 @@
+package main
+
+import (
+	"image/color"
+
+	"client.go/engine"
+	"client.go/strategy"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+)
+
+var showHeatmap bool                   //true if opponent's field is shaded by estimated probability of ships
+var heatmapCells [][]*canvas.Rectangle //shades over cells of opponent's field, nil when game container isn't shown
+var heatmapContainer *fyne.Container   //overlay which contains heatmapCells
+
+//Returns overlay of given size for opponent's field of given rules, which shades every cell
+//by estimated probability that it holds a ship. Rectangles don't handle taps, so cells below can be shot
+func newHeatmap(rules engine.Rules, size fyne.Size) *fyne.Container {
+	heatmapContainer = container.NewGridWithColumns(rules.Width)
+	heatmapCells = make([][]*canvas.Rectangle, rules.Height)
+	for x := range heatmapCells {
+		heatmapCells[x] = make([]*canvas.Rectangle, rules.Width)
+		for y := range heatmapCells[x] {
+			heatmapCells[x][y] = canvas.NewRectangle(color.Transparent)
+			heatmapContainer.Add(heatmapCells[x][y])
+		}
+	}
+	heatmapContainer.Resize(size)
+	updateHeatmap()
+
+	return heatmapContainer
+}
+
+//Shades cells of opponent's field by probability estimated from results of user's shots.
+//It is called whenever the field is rendered, so it must be fast even on big boards.
+//Caller must hold gameMu, because bot's field is changed by polling and events goroutines
+func updateHeatmap() {
+	if heatmapContainer == nil {
+		return
+	}
+	if !showHeatmap {
+		heatmapContainer.Hide()
+		return
+	}
+
+	probability := strategy.Probability(botBoard)
+	for x, row := range heatmapCells {
+		for y, cell := range row {
+			cell.FillColor = color.NRGBA{R: 0xff, G: 0x45, A: uint8(200 * probability[x][y])}
+		}
+	}
+	heatmapContainer.Show()
+	heatmapContainer.Refresh()
+}
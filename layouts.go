@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"client.go/engine"
+	"client.go/local"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+//SavedLayout is fleet layout saved by user under some name
+type SavedLayout struct {
+	Rules  string //name of rules layout was made for
+	Layout engine.Layout
+}
+
+//Returns path of library of saved layouts: <user config dir>/seabattle/layouts.json
+func layoutsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "seabattle", "layouts.json")
+}
+
+//Reads library of saved layouts by their names. Missing library is empty
+func readLayouts() (map[string]SavedLayout, error) {
+	layouts := make(map[string]SavedLayout)
+
+	data, err := os.ReadFile(layoutsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return layouts, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read layouts: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return nil, fmt.Errorf("read layouts: %w", err)
+	}
+
+	return layouts, nil
+}
+
+//Writes library of saved layouts
+func writeLayouts(layouts map[string]SavedLayout) error {
+	path := layoutsPath()
+	if path == "" {
+		return errors.New("save layouts: user config directory is unknown")
+	}
+
+	data, err := json.MarshalIndent(layouts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save layouts: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("save layouts: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+//Returns sorted names of saved layouts which were saved under given rules and have their board size.
+//Layouts of other rules of the same size may have ships which these rules don't allow
+func layoutNames(layouts map[string]SavedLayout, rules engine.Rules) []string {
+	var names []string
+	for name, saved := range layouts {
+		if saved.Rules == rules.Name && saved.Layout.Width == rules.Width && saved.Layout.Height == rules.Height {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//Replaces user's fleet with given layout. Every ship is validated like it was placed by hand
+func applyLayout(editor *placementEditor, layout engine.Layout) {
+	board, err := engine.PlaceLayout(userBoard.Rules, layout)
+	if err != nil {
+		showError(fmt.Errorf("layout: %w", err))
+		return
+	}
+
+	editor.do(func() error {
+		userBoard = board
+		return nil
+	})
+}
+
+//Places user's fleet by generator of given style. Empty seed is replaced with random one,
+//which is returned, so the same layout can be generated again
+func generateLayout(editor *placementEditor, styleName string, seed string) (string, error) {
+	style, err := engine.ParseStyle(styleName)
+	if err != nil {
+		return seed, err
+	}
+
+	n, err := parseSeed(seed)
+	if err != nil {
+		return seed, err
+	}
+	seed = strconv.FormatInt(n, 10)
+
+	board := engine.NewBoard(userBoard.Rules)
+	if err := engine.NewGenerator(style, seededRand(n, local.UserFleet)).Place(board); err != nil {
+		return seed, err
+	}
+	editor.do(func() error {
+		userBoard = board
+		return nil
+	})
+
+	return seed, nil
+}
+
+//Shows library of saved layouts. User's fleet can be saved under a name, loaded from library,
+//or exported and imported as text: ASCII grid, where every ship is marked with its own letter, or JSON.
+//Fleet can be generated in one of placement styles too
+func showLayoutsDialog(window fyne.Window, editor *placementEditor) {
+	layouts, err := readLayouts()
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	layoutSelect := widget.NewSelect(layoutNames(layouts, userBoard.Rules), nil)
+	layoutSelect.PlaceHolder = "Saved layouts"
+
+	loadButton := widget.NewButton("Load", func() {
+		saved, ok := layouts[layoutSelect.Selected]
+		if !ok {
+			return
+		}
+		applyLayout(editor, saved.Layout)
+	})
+	deleteButton := widget.NewButton("Delete", func() {
+		if _, ok := layouts[layoutSelect.Selected]; !ok {
+			return
+		}
+
+		delete(layouts, layoutSelect.Selected)
+		if err := writeLayouts(layouts); err != nil {
+			showError(err)
+		}
+		layoutSelect.Options = layoutNames(layouts, userBoard.Rules)
+		layoutSelect.ClearSelected()
+	})
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Layout name")
+	saveButton := widget.NewButton("Save", func() {
+		if nameEntry.Text == "" {
+			showError(errors.New("enter name of layout"))
+			return
+		}
+		if !userBoard.IsComplete() {
+			showError(errors.New("fleet is not complete"))
+			return
+		}
+
+		layouts[nameEntry.Text] = SavedLayout{Rules: userBoard.Rules.Name, Layout: userBoard.Layout()}
+		if err := writeLayouts(layouts); err != nil {
+			showError(err)
+			return
+		}
+		layoutSelect.Options = layoutNames(layouts, userBoard.Rules)
+		layoutSelect.SetSelected(nameEntry.Text)
+	})
+
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetPlaceHolder("Paste layout here to import it")
+	textEntry.TextStyle = fyne.TextStyle{Monospace: true}
+
+	asciiButton := widget.NewButton("Export ASCII", func() {
+		text, err := userBoard.Layout().ASCII()
+		if err != nil {
+			showError(err)
+			return
+		}
+		textEntry.SetText(text)
+	})
+	jsonButton := widget.NewButton("Export JSON", func() {
+		text, err := userBoard.Layout().JSON()
+		if err != nil {
+			showError(err)
+			return
+		}
+		textEntry.SetText(text)
+	})
+	importButton := widget.NewButton("Import", func() {
+		layout, err := engine.ParseLayout(textEntry.Text)
+		if err != nil {
+			showError(err)
+			return
+		}
+		applyLayout(editor, layout)
+	})
+
+	styleSelect := widget.NewSelect(engine.StyleNames[:], nil)
+	styleSelect.SetSelected(engine.StyleUniform.String())
+	seedEntry := widget.NewEntry()
+	seedEntry.SetPlaceHolder("Seed")
+	generateButton := widget.NewButton("Generate", func() {
+		seed, err := generateLayout(editor, styleSelect.Selected, seedEntry.Text)
+		if err != nil {
+			showError(err)
+			return
+		}
+		seedEntry.SetText(seed)
+	})
+
+	content := container.NewVBox(
+		container.NewBorder(nil, nil, styleSelect, generateButton, seedEntry),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, container.NewHBox(loadButton, deleteButton), layoutSelect),
+		container.NewBorder(nil, nil, nil, saveButton, nameEntry),
+		widget.NewSeparator(),
+		container.NewGridWrap(fyne.NewSize(360, 240), textEntry),
+		container.NewGridWithColumns(3, asciiButton, jsonButton, importButton),
+	)
+	dialog.ShowCustom("Fleet layouts", "Close", content, window)
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"client.go/api"
+	"client.go/profile"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Sources of leaderboard
+const (
+	localLeaderboard  = "Local profiles"
+	serverLeaderboard = "Server"
+)
+
+//Option of rules filter which passes games of all rules
+const allRulesOption = "All rules"
+
+//timeRange is option of time filter of leaderboard
+type timeRange struct {
+	name  string
+	since func(now time.Time) time.Time
+}
+
+var timeRanges = []timeRange{
+	{"All time", func(time.Time) time.Time { return time.Time{} }},
+	{"Today", func(now time.Time) time.Time {
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	}},
+	{"Last 7 days", func(now time.Time) time.Time { return now.AddDate(0, 0, -7) }},
+	{"Last 30 days", func(now time.Time) time.Time { return now.AddDate(0, 0, -30) }},
+	{"Last year", func(now time.Time) time.Time { return now.AddDate(-1, 0, 0) }},
+}
+
+//Returns names of time ranges
+func timeRangeNames() []string {
+	names := make([]string, len(timeRanges))
+	for i, r := range timeRanges {
+		names[i] = r.name
+	}
+
+	return names
+}
+
+//Returns filter of leaderboard for options chosen by user
+func leaderboardFilter(rulesOption string, rangeName string) profile.Filter {
+	var filter profile.Filter
+	if rulesOption != allRulesOption {
+		filter.Rules = rulesOption
+	}
+	for _, r := range timeRanges {
+		if r.name == rangeName {
+			filter.Since = r.since(time.Now())
+		}
+	}
+
+	return filter
+}
+
+//Returns standings of local players who have games passing filter
+func localStandings(store *profileStore, filter profile.Filter) []profile.Standing {
+	var standings []profile.Standing
+	for _, name := range store.names() {
+		if standing := store.Profiles[name].Standing(filter); standing.Games > 0 {
+			standings = append(standings, standing)
+		}
+	}
+
+	return standings
+}
+
+//Returns standings of server's leaderboard
+func serverStandings(ctx context.Context, client *api.Client, filter profile.Filter) ([]profile.Standing, error) {
+	entries, err := client.Leaderboard(ctx, filter.Rules, filter.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]profile.Standing, len(entries))
+	for i, entry := range entries {
+		standings[i] = profile.Standing(entry)
+	}
+
+	return standings, nil
+}
+
+//Returns options of rules filter: all rules, presets and rules of local players' games
+func leaderboardRules(store *profileStore) []string {
+	options := append([]string{allRulesOption}, presetNames()...)
+	known := make(map[string]bool)
+	for _, option := range options {
+		known[option] = true
+	}
+
+	var custom []string
+	for _, p := range store.Profiles {
+		for _, game := range p.Games {
+			if !known[game.Rules] {
+				known[game.Rules] = true
+				custom = append(custom, game.Rules)
+			}
+		}
+	}
+	sort.Strings(custom)
+
+	return append(options, custom...)
+}
+
+//Initializes leaderboard container. Standings are read from local profiles or from server,
+//sorted by rating, wins or fewest shots to win and filtered by rules and time range
+func newLeaderboardContainer(window fyne.Window) {
+	profilesMu.Lock()
+	store, err := readProfiles()
+	profilesMu.Unlock()
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	titleLabel := widget.NewLabelWithStyle("Leaderboard", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	titleLabel.Move(fyne.NewPos(30, 15))
+
+	sourceSelect := widget.NewSelect([]string{localLeaderboard, serverLeaderboard}, nil)
+	sourceSelect.SetSelected(localLeaderboard)
+	rulesSelect := widget.NewSelect(leaderboardRules(store), nil)
+	rulesSelect.SetSelected(allRulesOption)
+	rangeSelect := widget.NewSelect(timeRangeNames(), nil)
+	rangeSelect.SetSelected(timeRanges[0].name)
+	orderSelect := widget.NewSelect(profile.OrderNames[:], nil)
+	orderSelect.SetSelected(profile.ByRating.String())
+
+	controls := container.NewGridWithColumns(4, sourceSelect, rulesSelect, rangeSelect, orderSelect)
+	controls.Resize(fyne.NewSize(640, controls.MinSize().Height))
+	controls.Move(fyne.NewPos(30, 55))
+
+	table := container.NewGridWithColumns(6)
+	scroll := container.NewVScroll(table)
+	scroll.Move(fyne.NewPos(30, 110))
+	scroll.Resize(fyne.NewSize(640, window.Canvas().Size().Height-190))
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.Move(fyne.NewPos(30, window.Canvas().Size().Height-60))
+
+	//Fills table with standings in order chosen by user
+	show := func(standings []profile.Standing) {
+		order, _ := profile.ParseOrder(orderSelect.Selected)
+		profile.Sort(standings, order)
+
+		table.Objects = nil
+		for _, header := range []string{"#", "Player", "Rating", "Games", "Wins", "Fewest shots"} {
+			table.Add(widget.NewLabelWithStyle(header, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		}
+		for i, standing := range standings {
+			rating, fewestShots := "-", "-"
+			if standing.Rating != 0 {
+				rating = fmt.Sprintf("%.0f", standing.Rating)
+			}
+			if standing.FewestShots != 0 {
+				fewestShots = fmt.Sprint(standing.FewestShots)
+			}
+
+			table.Add(widget.NewLabel(fmt.Sprint(i + 1)))
+			table.Add(widget.NewLabel(standing.Player))
+			table.Add(widget.NewLabel(rating))
+			table.Add(widget.NewLabel(fmt.Sprint(standing.Games)))
+			table.Add(widget.NewLabel(fmt.Sprint(standing.Wins)))
+			table.Add(widget.NewLabel(fewestShots))
+		}
+		table.Refresh()
+		scroll.Refresh()
+
+		if len(standings) == 0 {
+			statusLabel.SetText("No games match the filter")
+		} else {
+			statusLabel.SetText(fmt.Sprintf("%d players", len(standings)))
+		}
+	}
+
+	//Request to server which is in progress. It is cancelled when filter changes or user leaves,
+	//so response to older filter doesn't overwrite table. mu guards table and cancelFetch
+	var mu sync.Mutex
+	cancelFetch := context.CancelFunc(func() {})
+
+	//Reads standings from chosen source. Server is asked in background, so window doesn't freeze
+	update := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		cancelFetch()
+		filter := leaderboardFilter(rulesSelect.Selected, rangeSelect.Selected)
+		if sourceSelect.Selected == localLeaderboard {
+			show(localStandings(store, filter))
+			return
+		}
+
+		statusLabel.SetText("Loading...")
+		client := apiClient
+		ctx, cancel := context.WithTimeout(context.Background(), api.DefaultTimeout)
+		cancelFetch = cancel
+		go func() {
+			defer cancel()
+
+			standings, err := serverStandings(ctx, client, filter)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case ctx.Err() == context.Canceled:
+				//filter was changed while request was in progress
+			case api.IsUnsupported(err):
+				show(nil)
+				statusLabel.SetText("Server " + client.BaseURL + " has no leaderboard")
+			case err != nil:
+				show(nil)
+				statusLabel.SetText("Server is unavailable")
+				fmt.Println(err)
+			default:
+				show(standings)
+			}
+		}()
+	}
+	for _, s := range []*widget.Select{sourceSelect, rulesSelect, rangeSelect, orderSelect} {
+		s.OnChanged = func(string) { update() }
+	}
+	update()
+
+	backButton := widget.NewButton("Back", func() {
+		mu.Lock()
+		cancelFetch()
+		mu.Unlock()
+		newMainContainer(window)
+	})
+	backButton.Move(fyne.NewPos(window.Canvas().Size().Width-150, window.Canvas().Size().Height-60))
+	backButton.Resize(fyne.NewSize(100, 45))
+
+	leaderboardContainer := container.NewWithoutLayout(titleLabel, controls, scroll, statusLabel, backButton)
+	leaderboardContainer.Refresh()
+	window.SetContent(leaderboardContainer)
+
+	window.SetTitle("Sea Battle: Leaderboard")
+}
@@ -0,0 +1,421 @@
+//Package local implements in-process bot opponent. Server answers the same calls
+//as api.Client, so client can play without remote server
+package local
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"client.go/api"
+	"client.go/engine"
+	"client.go/fairplay"
+	"client.go/strategy"
+)
+
+//BotName is used as Player2 of every local game
+const BotName = "bot"
+
+var (
+	ErrUnknownGame = errors.New("game not found")
+	ErrNotYourTurn = errors.New("it is bot's turn now")
+	ErrWrongMode   = errors.New("shot doesn't match game mode: salvo games accept only salvos")
+)
+
+//Stream is independent sequence of random decisions derived from seed of game
+type Stream int
+
+//Streams of game's random decisions. Each of them has its own source of randomness,
+//so, for example, bot's fleet isn't placed the same way as user's random fleet of the same seed
+const (
+	UserFleet Stream = iota //"Random ships" button of client
+	BotFleet
+	BotShots //strategy of bot
+	CoinToss //first turn
+)
+
+//Returns source of randomness for given stream of game with given seed.
+//Seeds of streams are drawn in turn from source of game's seed
+func SeededRand(seed int64, stream Stream) *rand.Rand {
+	master := rand.New(rand.NewSource(seed))
+	streamSeed := master.Int63()
+	for i := Stream(0); i < stream; i++ {
+		streamSeed = master.Int63()
+	}
+
+	return rand.New(rand.NewSource(streamSeed))
+}
+
+//Server keeps local games and plays as bot in each of them
+type Server struct {
+	mu       sync.Mutex
+	games    map[string]*game
+	lastID   int
+	strategy strategy.Strategy
+}
+
+type game struct {
+	data           api.GameData
+	rules          engine.Rules
+	fleet          *engine.Board //bot's own field
+	target         *engine.Board //results of bot's shots on user's field
+	user           *engine.Board //user's fleet, it is kept only in salvo games
+	pending        bool          //true if bot's shot was sent to user and its result is not reported yet
+	salt           []byte        //salt of bot's fleet commitment
+	userCommitment string
+	botShots       []fairplay.Shot //bot's shots and results reported by user
+}
+
+//Creates new local server. Bot chooses its shots with given strategy. To reproduce games,
+//strategy's source of randomness should be SeededRand(seed, BotShots) with seed of new game request
+func NewServer(s strategy.Strategy) *Server {
+	return &Server{
+		games:    make(map[string]*game),
+		strategy: s,
+	}
+}
+
+//Creates new game against bot. Bot's fleet is placed randomly by the same rules as user's fleet
+//and bot commits to it. Fleet and first turn are chosen with seed of request, so the same seed
+//and the same user's shots reproduce the game. User's fleet is ignored: in offline games user's client resolves bot's
+//shots itself, its honesty is checked with commitment when game ends.
+//In salvo games bot's salvo is resolved at once, so bot resolves it on user's fleet.
+//Classic rules are used if request has no rules
+func (s *Server) CreateGame(ctx context.Context, newGame api.NewGame) (api.GameData, error) {
+	rules := newGame.Rules
+	if rules.Fleet == nil {
+		rules = engine.Classic
+	}
+	if err := rules.Validate(); err != nil {
+		return api.GameData{}, err
+	}
+
+	salt, err := fairplay.NewSalt()
+	if err != nil {
+		return api.GameData{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	g := &game{
+		data: api.GameData{
+			GameID:  fmt.Sprintf("local-%d", s.lastID),
+			Player1: newGame.Username,
+			Player2: BotName,
+			Turn:    "user",
+		},
+		rules:          rules,
+		fleet:          engine.NewBoard(rules),
+		target:         engine.NewBoard(rules),
+		salt:           salt,
+		userCommitment: newGame.Commitment,
+	}
+	if rules.IsSalvo() {
+		if g.user, err = userFleet(rules, newGame.Fleet); err != nil {
+			return api.GameData{}, err
+		}
+	}
+	if err := g.fleet.PlaceRandomly(SeededRand(newGame.Seed, BotFleet)); err != nil {
+		return api.GameData{}, err
+	}
+	g.data.BotCommitment = fairplay.Commit(g.fleet.Fleet.Array, salt)
+	g.data.Seed = newGame.Seed
+	s.games[g.data.GameID] = g
+
+	//coin toss: bot makes the first turn at once
+	if SeededRand(newGame.Seed, CoinToss).Intn(2) == 1 {
+		if rules.IsSalvo() {
+			s.botSalvo(g)
+		} else {
+			s.botShot(g)
+		}
+	}
+
+	return g.data, nil
+}
+
+//Applies user's shot to bot's field. If user missed, bot makes its shot
+func (s *Server) Shoot(ctx context.Context, gameID string, x int, y int) (api.GameData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return api.GameData{}, ErrUnknownGame
+	}
+
+	//client doesn't report bot's misses, next user's shot means that bot missed
+	if g.pending {
+		g.target.Mark(engine.Point{X: g.data.BotX, Y: g.data.BotY}, engine.ShotMiss)
+		g.botShots = append(g.botShots, fairplay.Shot{X: g.data.BotX, Y: g.data.BotY, Result: engine.ShotMiss})
+		g.pending = false
+		g.data.BotLastShot = engine.ShotMiss.String()
+		g.data.Turn = "user"
+	}
+	if g.data.Turn != "user" {
+		return api.GameData{}, ErrNotYourTurn
+	}
+	if g.rules.IsSalvo() {
+		return api.GameData{}, ErrWrongMode
+	}
+
+	result, err := g.fleet.Receive(engine.Point{X: x, Y: y})
+	if err != nil {
+		return api.GameData{}, fmt.Errorf("shoot (%d, %d): %w", x, y, err)
+	}
+
+	g.data.UserX = x
+	g.data.UserY = y
+	g.data.UserLastShot = result.String()
+
+	if result == engine.ShotMiss {
+		s.botShot(g)
+	}
+
+	return g.data, nil
+}
+
+//Applies user's salvo to bot's field. Unless bot's fleet is destroyed, bot fires its salvo
+//on user's fleet and turn returns to user
+func (s *Server) ShootSalvo(ctx context.Context, gameID string, targets []api.Shot) (api.GameData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return api.GameData{}, ErrUnknownGame
+	}
+	if !g.rules.IsSalvo() {
+		return api.GameData{}, ErrWrongMode
+	}
+	if g.data.Turn != "user" {
+		return api.GameData{}, ErrNotYourTurn
+	}
+	if allowed := g.rules.ShotsPerTurn(g.user.Fleet); len(targets) == 0 || len(targets) > allowed {
+		return api.GameData{}, fmt.Errorf("salvo of %d shots, %d allowed", len(targets), allowed)
+	}
+
+	//salvo is checked before it is applied, so invalid salvo doesn't change the game
+	seen := make(map[engine.Point]bool, len(targets))
+	for _, target := range targets {
+		p := engine.Point{X: target.X, Y: target.Y}
+		if !g.fleet.InBounds(p) {
+			return api.GameData{}, fmt.Errorf("shoot (%d, %d): %w", p.X, p.Y, engine.ErrOutOfBoard)
+		}
+		if _, shot := g.fleet.ResultAt(p); shot || seen[p] {
+			return api.GameData{}, fmt.Errorf("shoot (%d, %d): %w", p.X, p.Y, engine.ErrAlreadyShot)
+		}
+		seen[p] = true
+	}
+
+	g.data.UserShots = make([]api.Shot, 0, len(targets))
+	for _, target := range targets {
+		//shot at cell covered by ship killed earlier in the same salvo is missed
+		result, _ := g.fleet.Receive(engine.Point{X: target.X, Y: target.Y})
+		g.data.UserShots = append(g.data.UserShots, api.Shot{X: target.X, Y: target.Y, Result: result.String()})
+		g.data.UserX, g.data.UserY, g.data.UserLastShot = target.X, target.Y, result.String()
+	}
+
+	g.data.BotShots = nil
+	if !g.fleet.FleetDestroyed() {
+		s.botSalvo(g)
+	}
+
+	return g.data, nil
+}
+
+//Records result of bot's shot and, because bot keeps its turn, makes next shot unless user's fleet is sunk
+func (s *Server) ReportBotShot(ctx context.Context, gameID string, x int, y int, result string) (api.GameData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return api.GameData{}, ErrUnknownGame
+	}
+
+	shotResult, err := engine.ParseShotResult(result)
+	if err != nil {
+		return api.GameData{}, err
+	}
+	if !g.pending || x != g.data.BotX || y != g.data.BotY {
+		return api.GameData{}, fmt.Errorf("bot didn't shoot (%d, %d)", x, y)
+	}
+
+	g.target.Mark(engine.Point{X: x, Y: y}, shotResult)
+	g.botShots = append(g.botShots, fairplay.Shot{X: x, Y: y, Result: shotResult})
+	g.pending = false
+	g.data.BotLastShot = result
+
+	//bot doesn't shoot after the last user's ship is sunk, game is over
+	if shotResult == engine.ShotMiss || g.target.FleetDestroyed() {
+		g.data.Turn = "user"
+	} else {
+		s.botShot(g)
+	}
+
+	return g.data, nil
+}
+
+//Checks user's revealed fleet against its commitment and bot's shots, then reveals bot's fleet.
+//Bot's fleet is revealed even if user cheated, error describes user's cheating
+func (s *Server) Reveal(ctx context.Context, gameID string, reveal api.RevealData) (api.RevealData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return api.RevealData{}, ErrUnknownGame
+	}
+
+	botReveal := api.RevealData{
+		GameID: gameID,
+		Fleet:  api.FleetData(g.fleet.Fleet),
+		Salt:   hex.EncodeToString(g.salt),
+	}
+
+	ships, err := api.ShipsFromData(reveal.Fleet)
+	if err != nil {
+		return botReveal, err
+	}
+	salt, err := hex.DecodeString(reveal.Salt)
+	if err != nil {
+		return botReveal, err
+	}
+	if err := fairplay.Audit(g.userCommitment, g.rules, ships, salt, g.botShots); err != nil {
+		return botReveal, fmt.Errorf("user's fleet: %w", err)
+	}
+
+	return botReveal, nil
+}
+
+//Closes local game
+func (s *Server) EndGame(ctx context.Context, gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.games[gameID]; !ok {
+		return ErrUnknownGame
+	}
+	delete(s.games, gameID)
+
+	return nil
+}
+
+//Chooses bot's next shot on user's field with server's strategy
+func (s *Server) botShot(g *game) {
+	p := s.strategy.NextShot(g.target)
+	if g.target.At(p) != engine.CellEmpty {
+		//there are no cells left to shoot
+		g.data.Turn = "user"
+		return
+	}
+
+	g.data.BotX = p.X
+	g.data.BotY = p.Y
+	g.data.Turn = "bot"
+	g.pending = true
+}
+
+//Fires bot's salvo on user's fleet and passes turn to user
+func (s *Server) botSalvo(g *game) {
+	for _, p := range strategy.Salvo(s.strategy, g.target, g.rules.ShotsPerTurn(g.fleet.Fleet)) {
+		//cell may be covered by ship killed earlier in the same salvo, then the shot is missed
+		result, err := g.user.Receive(p)
+		if err != nil && !errors.Is(err, engine.ErrAlreadyShot) {
+			continue
+		}
+
+		g.target.Mark(p, result)
+		g.botShots = append(g.botShots, fairplay.Shot{X: p.X, Y: p.Y, Result: result})
+		g.data.BotShots = append(g.data.BotShots, api.Shot{X: p.X, Y: p.Y, Result: result.String()})
+		g.data.BotX, g.data.BotY, g.data.BotLastShot = p.X, p.Y, result.String()
+	}
+
+	g.data.Turn = "user"
+}
+
+//Places user's fleet received in new game request on new board
+func userFleet(rules engine.Rules, fleet []api.ShipData) (*engine.Board, error) {
+	ships, err := api.ShipsFromData(fleet)
+	if err != nil {
+		return nil, err
+	}
+
+	board := engine.NewBoard(rules)
+	for _, ship := range ships {
+		if err := board.Place(ship); err != nil {
+			return nil, fmt.Errorf("user's fleet: %w", err)
+		}
+	}
+	if !board.IsComplete() {
+		return nil, errors.New("user's fleet is incomplete")
+	}
+
+	return board, nil
+}
+
+//GameState contains whole state of local game, so game can be saved and restored later
+type GameState struct {
+	Data           api.GameData
+	Rules          engine.Rules
+	Fleet          *engine.Board
+	Target         *engine.Board
+	User           *engine.Board `json:",omitempty"`
+	Pending        bool
+	Salt           []byte
+	UserCommitment string
+	BotShots       []fairplay.Shot
+}
+
+//Returns state of local game
+func (s *Server) State(gameID string) (GameState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return GameState{}, ErrUnknownGame
+	}
+
+	return GameState{
+		Data:           g.data,
+		Rules:          g.rules,
+		Fleet:          g.fleet,
+		Target:         g.target,
+		User:           g.user,
+		Pending:        g.pending,
+		Salt:           g.salt,
+		UserCommitment: g.userCommitment,
+		BotShots:       g.botShots,
+	}, nil
+}
+
+//Restores game saved with State. Bot continues game with server's strategy
+func (s *Server) Restore(state GameState) error {
+	if state.Fleet == nil || state.Target == nil || (state.Rules.IsSalvo() && state.User == nil) {
+		return fmt.Errorf("restore game %s: state is incomplete", state.Data.GameID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.games[state.Data.GameID] = &game{
+		data:           state.Data,
+		rules:          state.Rules,
+		fleet:          state.Fleet,
+		target:         state.Target,
+		user:           state.User,
+		pending:        state.Pending,
+		salt:           state.Salt,
+		userCommitment: state.UserCommitment,
+		botShots:       state.BotShots,
+	}
+
+	return nil
+}
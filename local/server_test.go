@@ -0,0 +1,129 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"client.go/api"
+	"client.go/engine"
+	"client.go/fairplay"
+	"client.go/strategy"
+)
+
+//Creates game with given seed on new server and returns server's state of it
+func newTestGame(t *testing.T, rules engine.Rules, seed int64) *game {
+	t.Helper()
+
+	bot, err := strategy.New(strategy.RandomName, SeededRand(seed, BotShots))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(bot)
+	data, err := s.CreateGame(context.Background(), api.NewGame{Username: "user", Rules: rules, Seed: seed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s.games[data.GameID]
+}
+
+func TestFleetsOfSameSeedDiffer(t *testing.T) {
+	for _, rules := range []engine.Rules{engine.Classic, engine.MiltonBradley} {
+		for _, seed := range []int64{0, 1, 42, -7} {
+			user := engine.NewBoard(rules)
+			if err := user.PlaceRandomly(SeededRand(seed, UserFleet)); err != nil {
+				t.Fatal(err)
+			}
+			g := newTestGame(t, rules, seed)
+
+			if bytes.Equal(fairplay.Serialize(user.Fleet.Array), fairplay.Serialize(g.fleet.Fleet.Array)) {
+				t.Errorf("%s, seed %d: bot's fleet is the same as user's random fleet", rules.Name, seed)
+			}
+		}
+	}
+}
+
+func TestSameSeedReproducesGame(t *testing.T) {
+	for _, seed := range []int64{0, 1, 42} {
+		first, second := newTestGame(t, engine.Classic, seed), newTestGame(t, engine.Classic, seed)
+
+		if !bytes.Equal(fairplay.Serialize(first.fleet.Fleet.Array), fairplay.Serialize(second.fleet.Fleet.Array)) {
+			t.Errorf("seed %d: bot's fleets differ", seed)
+		}
+		if first.data.Turn != second.data.Turn || first.data.BotX != second.data.BotX || first.data.BotY != second.data.BotY {
+			t.Errorf("seed %d: first turns differ: %+v, %+v", seed, first.data, second.data)
+		}
+	}
+}
+
+func TestSeededRandStreams(t *testing.T) {
+	streams := []Stream{UserFleet, BotFleet, BotShots, CoinToss}
+	seen := make(map[int64]Stream)
+
+	for _, stream := range streams {
+		n := SeededRand(42, stream).Int63()
+		if other, ok := seen[n]; ok {
+			t.Errorf("streams %d and %d start with the same number", other, stream)
+		}
+		seen[n] = stream
+
+		if SeededRand(42, stream).Int63() != n {
+			t.Errorf("stream %d isn't reproducible", stream)
+		}
+	}
+}
+
+func TestBotStopsWhenUserFleetIsSunk(t *testing.T) {
+	bot, err := strategy.New(strategy.ProbabilityDensityName, SeededRand(5, BotShots))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(bot)
+	user := engine.NewBoard(engine.Classic)
+	if err := user.PlaceRandomly(SeededRand(5, UserFleet)); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	data, err := s.CreateGame(ctx, api.NewGame{Username: "user", Rules: engine.Classic, Seed: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//user shoots only cells of bot's field without ships, so bot gets turn after every user's shot
+	var misses []engine.Point
+	for _, p := range s.games[data.GameID].fleet.Points() {
+		if s.games[data.GameID].fleet.At(p) == engine.CellEmpty {
+			misses = append(misses, p)
+		}
+	}
+
+	for !user.FleetDestroyed() {
+		if data.Turn != "bot" {
+			if len(misses) == 0 {
+				t.Fatal("bot didn't sink user's fleet")
+			}
+			if data, err = s.Shoot(ctx, data.GameID, misses[0].X, misses[0].Y); err != nil {
+				t.Fatal(err)
+			}
+			misses = misses[1:]
+			continue
+		}
+
+		result, err := user.Receive(engine.Point{X: data.BotX, Y: data.BotY})
+		if err != nil {
+			t.Fatalf("bot shot (%d, %d): %v", data.BotX, data.BotY, err)
+		}
+		if result == engine.ShotMiss {
+			data.Turn = "user"
+			continue
+		}
+		if data, err = s.ReportBotShot(ctx, data.GameID, data.BotX, data.BotY, result.String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if data.Turn == "bot" || s.games[data.GameID].pending {
+		t.Errorf("bot shot (%d, %d) after user's fleet was sunk", data.BotX, data.BotY)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"client.go/api"
+	"fyne.io/fyne/v2"
+)
+
+//How often client asks server about state of multiplayer room
+const pollInterval = time.Second
+
+//Creates multiplayer room on current server and opens game container,
+//where user waits for opponent
+func createRoom(window fyne.Window, username string) {
+	if !readyToStart(username) {
+		return
+	}
+
+	newGame, err := newGameRequest(username, "")
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	client := api.NewClient(serverUri)
+	game, err := client.CreateRoom(context.Background(), newGame)
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	startRoom(window, client, game)
+}
+
+//Joins multiplayer room with given ID and opens game container
+func joinRoom(window fyne.Window, gameID string, username string) {
+	if gameID == "" {
+		fmt.Println("\nYou haven't entered room ID")
+		return
+	}
+	if !readyToStart(username) {
+		return
+	}
+
+	newGame, err := newGameRequest(username, gameID)
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	client := api.NewClient(serverUri)
+	game, err := client.JoinRoom(context.Background(), newGame)
+	if err != nil {
+		showError(err)
+		return
+	}
+
+	startRoom(window, client, game)
+}
+
+//Opens game container for multiplayer room and starts receiving its state
+func startRoom(window fyne.Window, client *api.Client, game api.GameData) {
+	backend = client
+	gameData = game
+	isRoom = true
+	resetGame()
+	newGameContainer(window)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopGame = cancel
+	go watchGame(ctx, client, game.GameID, true)
+}
+
+//Periodically requests state of multiplayer room until context is cancelled.
+//It is used only with servers which don't push events
+func pollRoom(ctx context.Context, client *api.Client, gameID string) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		game, err := client.RoomState(ctx, gameID)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		gameMu.Lock()
+		if ctx.Err() == nil {
+			applyGameState(ctx, game)
+		}
+		gameMu.Unlock()
+	}
+}
+
+//Resolves opponent's shot on user's field and reports its result to LegacyProtocol server, which
+//doesn't keep user's fleet. Unlike server's bot, opponent's client waits for result of every shot, including misses
+func reportOpponentShot(ctx context.Context) {
+	reporter, ok := backend.(botShotReporter)
+	if !ok {
+		return
+	}
+
+	analyzeBotShot(&gameData)
+	renderBoard(userCellArray, userBoard)
+
+	game, err := reporter.ReportBotShot(ctx, gameData.GameID, gameData.BotX, gameData.BotY, gameData.BotLastShot)
+	if err != nil {
+		if ctx.Err() == nil {
+			showError(err)
+		}
+		return
+	}
+	gameData = game
+}
+
+//Returns names of user and opponent in current game
+func playerNames() (string, string) {
+	if gameData.Player != "" && gameData.Player == gameData.Player2 {
+		return gameData.Player2, gameData.Player1
+	}
+
+	return gameData.Player1, gameData.Player2
+}
+
+//Shows whose turn it is now and name of opponent, who could join the room recently
+func updateStatus() {
+	if statusLabel == nil {
+		return
+	}
+
+	_, opponent := playerNames()
+	if opponent == "" {
+		opponent = "Opponent"
+	}
+	opponentLabel.SetText(opponent + "'s field:")
+
+	//game is won when every ship of rules is sunk
+	switch {
+	case botBoard.FleetDestroyed():
+		statusLabel.SetText("You won")
+	case userBoard.FleetDestroyed():
+		statusLabel.SetText(opponent + " won")
+	case gameData.Turn == api.TurnWaiting:
+		statusLabel.SetText("Waiting for opponent. Room ID: " + gameData.GameID)
+	case gameData.Turn == api.TurnOpponent, gameData.Turn == api.TurnBot:
+		statusLabel.SetText(opponent + "'s turn")
+	case botBoard.Rules.IsSalvo():
+		statusLabel.SetText(fmt.Sprintf("Your turn: %d of %d shots of salvo selected", len(salvoTargets), salvoSize()))
+	default:
+		statusLabel.SetText("Your turn")
+	}
+}
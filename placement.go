@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+
+	"client.go/engine"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+//placementEditor handles editing of user's fleet before game: placing and deleting ships by click,
+//moving them by drag and drop, rotating them in place by right click or "R" key. Every change
+//can be undone and redone. Ghost of ship which would be placed or moved is shown under cursor
+type placementEditor struct {
+	window    fyne.Window
+	root      fyne.CanvasObject //main container, keys are handled only while it is shown
+	container *fyne.Container   //container of user's field
+	cells     [][]Cell
+	ships     *shipControls
+
+	undo []*engine.Board //states of user's board before changes
+	redo []*engine.Board //states of user's board before undone changes
+
+	hovered  *engine.Point //cell under cursor
+	dragged  *engine.Ship  //ship which is moved by drag and drop
+	grabbed  engine.Point  //deck by which dragged ship was taken
+	dropping engine.Point  //cell under cursor during drag
+}
+
+//Returns new editor of user's board, which cells are set later by setButtons
+func newPlacementEditor(window fyne.Window, container *fyne.Container, ships *shipControls) *placementEditor {
+	editor := &placementEditor{window: window, container: container, ships: ships}
+
+	canvas := window.Canvas()
+	canvas.SetOnTypedKey(func(event *fyne.KeyEvent) {
+		if event.Name == fyne.KeyR && editor.active() && editor.hovered != nil {
+			editor.rotate(*editor.hovered)
+		}
+	})
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: desktop.ControlModifier}, func(fyne.Shortcut) {
+		if editor.active() {
+			editor.Undo()
+		}
+	})
+	canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: desktop.ControlModifier}, func(fyne.Shortcut) {
+		if editor.active() {
+			editor.Redo()
+		}
+	})
+
+	return editor
+}
+
+//Returns true if user's field of this editor is shown in window
+func (editor *placementEditor) active() bool {
+	return editor.root != nil && editor.window.Content() == editor.root
+}
+
+//Applies change to user's board and saves previous state for undo. Nothing is saved if change fails
+func (editor *placementEditor) do(change func() error) {
+	previous := userBoard.Clone()
+
+	if err := change(); err != nil {
+		fmt.Println("\n" + err.Error())
+		editor.render()
+		return
+	}
+
+	editor.undo = append(editor.undo, previous)
+	editor.redo = nil
+	editor.render()
+}
+
+//Restores state of user's board before last change
+func (editor *placementEditor) Undo() {
+	if len(editor.undo) == 0 {
+		return
+	}
+
+	editor.redo = append(editor.redo, userBoard)
+	userBoard = editor.undo[len(editor.undo)-1]
+	editor.undo = editor.undo[:len(editor.undo)-1]
+	editor.render()
+}
+
+//Applies last undone change again
+func (editor *placementEditor) Redo() {
+	if len(editor.redo) == 0 {
+		return
+	}
+
+	editor.undo = append(editor.undo, userBoard)
+	userBoard = editor.redo[len(editor.redo)-1]
+	editor.redo = editor.redo[:len(editor.redo)-1]
+	editor.render()
+}
+
+//Forgets history of changes, it is called when board is replaced with board of other rules
+func (editor *placementEditor) Reset() {
+	editor.undo, editor.redo = nil, nil
+	editor.hovered, editor.dragged = nil, nil
+}
+
+//Places selected ship to empty cell or deletes ship by its base deck
+func (editor *placementEditor) tap(cell Cell) {
+	editor.do(func() error {
+		return validateAreaForShip(cell, editor.ships.kind.Selected, editor.ships.rotation.Selected, editor.ships.mirror.Checked)
+	})
+}
+
+//Turns ship which occupies given cell clockwise around its base deck
+func (editor *placementEditor) rotate(p engine.Point) {
+	ship, ok := userBoard.ShipAt(p)
+	if !ok {
+		return
+	}
+
+	editor.do(func() error {
+		return userBoard.Replace(ship.BaseDeckPosition, ship.Rotate())
+	})
+}
+
+func (editor *placementEditor) hover(p engine.Point) {
+	editor.hovered = &p
+	editor.render()
+}
+
+func (editor *placementEditor) leave(p engine.Point) {
+	if editor.hovered != nil && *editor.hovered == p {
+		editor.hovered = nil
+		editor.render()
+	}
+}
+
+//Moves ghost of dragged ship to the cell under cursor. Drag starts at deck of ship
+func (editor *placementEditor) drag(p engine.Point, event *fyne.DragEvent) {
+	if editor.dragged == nil {
+		ship, ok := userBoard.ShipAt(p)
+		if !ok {
+			return
+		}
+		editor.dragged, editor.grabbed, editor.dropping = &ship, p, p
+	}
+
+	if target, ok := editor.cellAt(event.AbsolutePosition); ok {
+		editor.dropping = target
+		editor.render()
+	}
+}
+
+//Moves dragged ship to the place of its ghost
+func (editor *placementEditor) drop() {
+	if editor.dragged == nil {
+		return
+	}
+
+	ship := *editor.dragged
+	editor.dragged = nil
+	editor.do(func() error {
+		return userBoard.Replace(ship.BaseDeckPosition, editor.moved(ship))
+	})
+}
+
+//Returns dragged ship moved so that its grabbed deck is located in the cell under cursor
+func (editor *placementEditor) moved(ship engine.Ship) engine.Ship {
+	base := ship.BaseDeckPosition
+
+	return ship.MoveTo(engine.Point{
+		X: base.X + editor.dropping.X - editor.grabbed.X,
+		Y: base.Y + editor.dropping.Y - editor.grabbed.Y,
+	})
+}
+
+//Returns cell of user's field located at given absolute position
+func (editor *placementEditor) cellAt(position fyne.Position) (engine.Point, bool) {
+	rules := userBoard.Rules
+	origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(editor.container)
+	size := editor.container.Size()
+
+	x := (position.Y - origin.Y) * float32(rules.Height) / size.Height
+	y := (position.X - origin.X) * float32(rules.Width) / size.Width
+	p := engine.Point{X: int(x), Y: int(y)}
+	if x < 0 || y < 0 || !userBoard.InBounds(p) {
+		return p, false
+	}
+
+	return p, true
+}
+
+//Renders user's board with ghost of ship which would be placed or moved. Cells of ghost which can be
+//placed are marked with "o" and highlighted, cells of ghost which collides something are marked with "!"
+func (editor *placementEditor) render() {
+	renderBoard(editor.cells, userBoard)
+
+	if ghost, valid, ok := editor.ghost(); ok {
+		for _, p := range ghost.Cells() {
+			if !userBoard.InBounds(p) {
+				continue
+			}
+
+			button := editor.cells[p.X][p.Y].Button
+			if valid {
+				button.Importance = widget.HighImportance
+				button.SetText("o")
+			} else {
+				button.Importance = widget.LowImportance
+				button.SetText("!")
+			}
+		}
+	}
+
+	editor.container.Refresh()
+}
+
+//Returns ship which would be placed by click on hovered cell or moved by drop,
+//and true if it can be placed there
+func (editor *placementEditor) ghost() (engine.Ship, bool, bool) {
+	if editor.dragged != nil {
+		board := userBoard.Clone()
+		board.RemoveAt(editor.dragged.BaseDeckPosition)
+		ship := editor.moved(*editor.dragged)
+
+		return ship, board.CanPlace(ship), true
+	}
+
+	if editor.hovered == nil || userBoard.At(*editor.hovered) != engine.CellEmpty {
+		return engine.Ship{}, false, false
+	}
+
+	spec, ok := shipSpec(editor.ships.kind.Selected)
+	if !ok {
+		return engine.Ship{}, false, false
+	}
+	ship := spec.NewShip(*editor.hovered, shipRotations[editor.ships.rotation.Selected], editor.ships.mirror.Checked)
+
+	return ship, userBoard.CanPlace(ship) && userBoard.HaveFreeSpace(ship), true
+}
+
+//placementButton is cell of user's field which reports hover, drag and right click to placement editor
+type placementButton struct {
+	widget.Button
+	cell   Cell
+	editor *placementEditor
+}
+
+func newPlacementButton(cell Cell, editor *placementEditor) *placementButton {
+	button := &placementButton{cell: cell, editor: editor}
+	button.OnTapped = func() {
+		editor.tap(cell)
+	}
+	button.ExtendBaseWidget(button)
+
+	return button
+}
+
+func (button *placementButton) MouseIn(event *desktop.MouseEvent) {
+	button.Button.MouseIn(event)
+	button.editor.hover(button.cell.point())
+}
+
+func (button *placementButton) MouseOut() {
+	button.Button.MouseOut()
+	button.editor.leave(button.cell.point())
+}
+
+func (button *placementButton) TappedSecondary(*fyne.PointEvent) {
+	button.editor.rotate(button.cell.point())
+}
+
+func (button *placementButton) Dragged(event *fyne.DragEvent) {
+	button.editor.drag(button.cell.point(), event)
+}
+
+func (button *placementButton) DragEnd() {
+	button.editor.drop()
+}
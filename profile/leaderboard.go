@@ -0,0 +1,121 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+//Filter selects games counted in leaderboard
+type Filter struct {
+	Rules string    //name of rules, empty for games of all rules
+	Since time.Time //games finished earlier are skipped, zero for games of all time
+}
+
+//Returns true if game passes filter
+func (f Filter) Match(result Result) bool {
+	if f.Rules != "" && result.Rules != f.Rules {
+		return false
+	}
+
+	return f.Since.IsZero() || !result.Finished.Before(f.Since)
+}
+
+//Standing is player's line in leaderboard
+type Standing struct {
+	Player      string
+	Rating      float64 `json:",omitempty"` //zero if player isn't rated
+	Games       int
+	Wins        int
+	FewestShots int `json:",omitempty"` //the least number of shots player needed to win, zero if player never won
+}
+
+//Returns standing of player counting only games which pass filter. Rating is the latest one
+//received after such game, or the current rating if filter passes all games
+func (p *Profile) Standing(filter Filter) Standing {
+	standing := Standing{Player: p.Name}
+
+	for _, game := range p.Games {
+		if !filter.Match(game) {
+			continue
+		}
+
+		standing.Games++
+		if game.Rating != 0 {
+			standing.Rating = game.Rating
+		}
+		if !game.Won {
+			continue
+		}
+		standing.Wins++
+		if standing.FewestShots == 0 || game.Shots < standing.FewestShots {
+			standing.FewestShots = game.Shots
+		}
+	}
+
+	if filter == (Filter{}) {
+		standing.Rating = p.Rating
+	}
+
+	return standing
+}
+
+//Order of leaderboard
+type Order int
+
+const (
+	ByRating      Order = iota //the highest rating first
+	ByWins                     //the most wins first
+	ByFewestShots              //the quickest win first, players without wins go last
+)
+
+//OrderNames contains names of leaderboard orders, indexed by Order
+var OrderNames = [...]string{
+	ByRating:      "rating",
+	ByWins:        "wins",
+	ByFewestShots: "fewest shots to win",
+}
+
+//Returns "rating", "wins" or "fewest shots to win"
+func (order Order) String() string {
+	if order < 0 || int(order) >= len(OrderNames) {
+		return fmt.Sprintf("Order(%d)", int(order))
+	}
+
+	return OrderNames[order]
+}
+
+//Converts name returned by String to Order
+func ParseOrder(s string) (Order, error) {
+	for order, name := range OrderNames {
+		if name == s {
+			return Order(order), nil
+		}
+	}
+
+	return ByRating, fmt.Errorf("unknown leaderboard order %q", s)
+}
+
+//Sorts standings in given order. Ties are broken by wins, then by name of player
+func Sort(standings []Standing, order Order) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+
+		switch order {
+		case ByRating:
+			if a.Rating != b.Rating {
+				return a.Rating > b.Rating
+			}
+		case ByFewestShots:
+			if a.FewestShots != b.FewestShots {
+				//zero means no wins, so it goes after any number of shots
+				return b.FewestShots == 0 || a.FewestShots != 0 && a.FewestShots < b.FewestShots
+			}
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+
+		return a.Player < b.Player
+	})
+}
@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStanding(t *testing.T) {
+	day := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := New("alice")
+	p.Add(Result{GameID: "1", Finished: day, Rules: "Classic", Won: true, Shots: 60, Rating: 1490})
+	p.Add(Result{GameID: "2", Finished: day.AddDate(0, 0, 1), Rules: "Polyomino", Won: true, Shots: 45, Rating: 1510})
+	p.Add(Result{GameID: "3", Finished: day.AddDate(0, 0, 2), Rules: "Classic", Shots: 70, Rating: 1500})
+	p.Add(Result{GameID: "4", Finished: day.AddDate(0, 0, 3), Rules: "Classic", Won: true, Shots: 52})
+	p.Rating = 1530
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   Standing
+	}{
+		{"all games", Filter{}, Standing{Player: "alice", Rating: 1530, Games: 4, Wins: 3, FewestShots: 45}},
+		{"rules", Filter{Rules: "Classic"}, Standing{Player: "alice", Rating: 1500, Games: 3, Wins: 2, FewestShots: 52}},
+		{"since", Filter{Since: day.AddDate(0, 0, 1)}, Standing{Player: "alice", Rating: 1500, Games: 3, Wins: 2, FewestShots: 45}},
+		{"rules and since", Filter{Rules: "Classic", Since: day.AddDate(0, 0, 3)}, Standing{Player: "alice", Games: 1, Wins: 1, FewestShots: 52}},
+		{"no games", Filter{Rules: "Russian"}, Standing{Player: "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Standing(tt.filter); got != tt.want {
+				t.Errorf("Standing(%+v) = %+v, want %+v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	standings := []Standing{
+		{Player: "dave", Rating: 1400, Games: 3},
+		{Player: "bob", Rating: 1550, Games: 5, Wins: 2, FewestShots: 48},
+		{Player: "erin", Games: 1},
+		{Player: "carol", Rating: 1550, Games: 6, Wins: 4, FewestShots: 52},
+		{Player: "alice", Rating: 1480, Games: 4, Wins: 2, FewestShots: 48},
+	}
+
+	tests := []struct {
+		order Order
+		want  []string
+	}{
+		{ByRating, []string{"carol", "bob", "alice", "dave", "erin"}},
+		{ByWins, []string{"carol", "alice", "bob", "dave", "erin"}},
+		//players without wins have zero shots and go last
+		{ByFewestShots, []string{"alice", "bob", "carol", "dave", "erin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			sorted := append([]Standing(nil), standings...)
+			Sort(sorted, tt.order)
+
+			var got []string
+			for _, standing := range sorted {
+				got = append(got, standing.Player)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sort(%s) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	for order := range OrderNames {
+		got, err := ParseOrder(Order(order).String())
+		if err != nil || got != Order(order) {
+			t.Errorf("ParseOrder(%q) = %v, %v", Order(order).String(), got, err)
+		}
+	}
+	if _, err := ParseOrder("luck"); err == nil {
+		t.Errorf("ParseOrder(\"luck\") returned no error")
+	}
+}
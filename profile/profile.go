@@ -0,0 +1,167 @@
+//Package profile keeps local player profiles: results of finished games, lifetime
+//statistics computed from them and rating given by server
+package profile
+
+import (
+	"time"
+
+	"client.go/record"
+)
+
+//Result is outcome of one finished game from the point of view of profile's player
+type Result struct {
+	GameID   string
+	Finished time.Time
+	Opponent string
+	Rules    string //name of rules
+	Won      bool
+	Shots    int
+	Hits     int
+	Duration time.Duration
+	Rating   float64 `json:",omitempty"` //rating on server after the game, zero if server doesn't rate players
+}
+
+//Returns result of user in finished game
+func NewResult(rec *record.GameRecord, won bool) Result {
+	stats := rec.Stats(record.User)
+
+	return Result{
+		GameID:   rec.GameID,
+		Finished: rec.Finished,
+		Opponent: rec.Opponent,
+		Rules:    rec.Rules.Name,
+		Won:      won,
+		Shots:    stats.Shots,
+		Hits:     stats.Hits,
+		Duration: rec.Duration(),
+	}
+}
+
+//Returns part of shots which hit a ship, from 0 to 1
+func (r Result) Accuracy() float64 {
+	if r.Shots == 0 {
+		return 0
+	}
+
+	return float64(r.Hits) / float64(r.Shots)
+}
+
+//Profile is local player with results of all games finished under its name
+type Profile struct {
+	Name    string
+	Created time.Time
+	Games   []Result //in order games were finished
+	//the latest rating on server, deviation is known only for Glicko ratings
+	Rating    float64 `json:",omitempty"`
+	Deviation float64 `json:",omitempty"`
+}
+
+//Creates profile without games
+func New(name string) *Profile {
+	return &Profile{Name: name, Created: time.Now()}
+}
+
+//Adds result of finished game. Result of game which is already in profile replaces it
+func (p *Profile) Add(result Result) {
+	for i, game := range p.Games {
+		if game.GameID == result.GameID {
+			p.Games[i] = result
+			return
+		}
+	}
+
+	p.Games = append(p.Games, result)
+}
+
+//Stores rating received from server after given game
+func (p *Profile) SetRating(gameID string, rating float64, deviation float64) {
+	p.Rating, p.Deviation = rating, deviation
+	for i := range p.Games {
+		if p.Games[i].GameID == gameID {
+			p.Games[i].Rating = rating
+		}
+	}
+}
+
+//Summary is lifetime statistics of profile
+type Summary struct {
+	Games       int
+	Wins        int
+	Losses      int
+	WinRate     float64 //part of games which were won, from 0 to 1
+	ShotsPerWin float64 //average number of shots fired in won games
+	Accuracy    float64 //part of all shots which hit a ship, from 0 to 1
+}
+
+//Returns lifetime statistics of profile
+func (p *Profile) Summary() Summary {
+	var summary Summary
+	shots, hits, winningShots := 0, 0, 0
+
+	for _, game := range p.Games {
+		summary.Games++
+		shots += game.Shots
+		hits += game.Hits
+		if game.Won {
+			summary.Wins++
+			winningShots += game.Shots
+		} else {
+			summary.Losses++
+		}
+	}
+
+	if summary.Games > 0 {
+		summary.WinRate = float64(summary.Wins) / float64(summary.Games)
+	}
+	if summary.Wins > 0 {
+		summary.ShotsPerWin = float64(winningShots) / float64(summary.Wins)
+	}
+	if shots > 0 {
+		summary.Accuracy = float64(hits) / float64(shots)
+	}
+
+	return summary
+}
+
+//Returns accuracy of every game and its moving average over the last 'window' games,
+//which shows whether player gets better. Window must be positive
+func (p *Profile) AccuracyTrend(window int) (accuracy []float64, average []float64) {
+	sum := 0.0
+	for i, game := range p.Games {
+		accuracy = append(accuracy, game.Accuracy())
+
+		sum += accuracy[i]
+		n := i + 1
+		if i >= window {
+			sum -= accuracy[i-window]
+			n = window
+		}
+		average = append(average, sum/float64(n))
+	}
+
+	return accuracy, average
+}
+
+//Returns shots fired in every won game
+func (p *Profile) ShotsPerWinHistory() []float64 {
+	var shots []float64
+	for _, game := range p.Games {
+		if game.Won {
+			shots = append(shots, float64(game.Shots))
+		}
+	}
+
+	return shots
+}
+
+//Returns ratings after rated games
+func (p *Profile) RatingHistory() []float64 {
+	var ratings []float64
+	for _, game := range p.Games {
+		if game.Rating != 0 {
+			ratings = append(ratings, game.Rating)
+		}
+	}
+
+	return ratings
+}
@@ -0,0 +1,122 @@
+package profile
+
+import (
+	"math"
+	"testing"
+)
+
+//Returns profile with given results, games are numbered in order
+func testProfile(games ...Result) *Profile {
+	p := New("alice")
+	for i, game := range games {
+		if game.GameID == "" {
+			game.GameID = string(rune('a' + i))
+		}
+		p.Add(game)
+	}
+
+	return p
+}
+
+//Returns true if floats are equal up to rounding errors
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		games []Result
+		want  Summary
+	}{
+		{"no games", nil, Summary{}},
+		{"one win", []Result{{Won: true, Shots: 40, Hits: 20}}, Summary{Games: 1, Wins: 1, WinRate: 1, ShotsPerWin: 40, Accuracy: 0.5}},
+		{"one loss", []Result{{Shots: 50, Hits: 10}}, Summary{Games: 1, Losses: 1, Accuracy: 0.2}},
+		{
+			"wins and losses",
+			[]Result{{Won: true, Shots: 40, Hits: 20}, {Shots: 60, Hits: 15}, {Won: true, Shots: 60, Hits: 20}, {Shots: 40, Hits: 5}},
+			Summary{Games: 4, Wins: 2, Losses: 2, WinRate: 0.5, ShotsPerWin: 50, Accuracy: 60.0 / 200},
+		},
+		{"no shots", []Result{{}, {}}, Summary{Games: 2, Losses: 2}},
+		{"win without shots", []Result{{Won: true}}, Summary{Games: 1, Wins: 1, WinRate: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testProfile(tt.games...).Summary()
+
+			if got.Games != tt.want.Games || got.Wins != tt.want.Wins || got.Losses != tt.want.Losses {
+				t.Errorf("got %d games, %d wins, %d losses, want %d, %d, %d",
+					got.Games, got.Wins, got.Losses, tt.want.Games, tt.want.Wins, tt.want.Losses)
+			}
+			if !near(got.WinRate, tt.want.WinRate) || !near(got.ShotsPerWin, tt.want.ShotsPerWin) ||
+				!near(got.Accuracy, tt.want.Accuracy) {
+				t.Errorf("Summary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddReplacesGame(t *testing.T) {
+	p := testProfile(Result{GameID: "1", Shots: 10}, Result{GameID: "2", Shots: 20})
+	p.Add(Result{GameID: "1", Shots: 30, Won: true})
+
+	if len(p.Games) != 2 || p.Games[0].Shots != 30 || !p.Games[0].Won {
+		t.Errorf("games are %+v, want game 1 replaced in place", p.Games)
+	}
+}
+
+func TestAccuracyTrend(t *testing.T) {
+	games := []Result{
+		{Shots: 10, Hits: 1},
+		{Shots: 10, Hits: 3},
+		{Shots: 0},
+		{Shots: 10, Hits: 8},
+	}
+
+	tests := []struct {
+		name     string
+		games    []Result
+		window   int
+		accuracy []float64
+		average  []float64
+	}{
+		{"no games", nil, 3, nil, nil},
+		{"window of one game", games, 1, []float64{0.1, 0.3, 0, 0.8}, []float64{0.1, 0.3, 0, 0.8}},
+		{"window of two games", games, 2, []float64{0.1, 0.3, 0, 0.8}, []float64{0.1, 0.2, 0.15, 0.4}},
+		{"window longer than history", games, 10, []float64{0.1, 0.3, 0, 0.8}, []float64{0.1, 0.2, 0.4 / 3, 0.3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accuracy, average := testProfile(tt.games...).AccuracyTrend(tt.window)
+
+			if len(accuracy) != len(tt.accuracy) || len(average) != len(tt.average) {
+				t.Fatalf("AccuracyTrend() = %v, %v, want %v, %v", accuracy, average, tt.accuracy, tt.average)
+			}
+			//values go in order games were finished
+			for i := range accuracy {
+				if !near(accuracy[i], tt.accuracy[i]) || !near(average[i], tt.average[i]) {
+					t.Errorf("game %d: accuracy %v, average %v, want %v, %v", i+1, accuracy[i], average[i], tt.accuracy[i], tt.average[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHistories(t *testing.T) {
+	p := testProfile(
+		Result{Won: true, Shots: 45},
+		Result{Shots: 70, Rating: 1490},
+		Result{Won: true, Shots: 38, Rating: 1510},
+	)
+
+	shots := p.ShotsPerWinHistory()
+	if len(shots) != 2 || shots[0] != 45 || shots[1] != 38 {
+		t.Errorf("ShotsPerWinHistory() = %v, want [45 38]", shots)
+	}
+	ratings := p.RatingHistory()
+	if len(ratings) != 2 || ratings[0] != 1490 || ratings[1] != 1510 {
+		t.Errorf("RatingHistory() = %v, want [1490 1510]", ratings)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"client.go/api"
+	"client.go/profile"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Games in moving average of accuracy on profile screen
+const accuracyTrendWindow = 5
+
+//Time to wait for rating from server after game
+const ratingTimeout = 10 * time.Second
+
+//profileStore contains profiles of all local players
+type profileStore struct {
+	Last     string //name of player who played the last game, it is offered in main container
+	Profiles map[string]*profile.Profile
+}
+
+//profilesMu guards profile store, which is updated by GUI and by goroutine receiving rating from server
+var profilesMu sync.Mutex
+
+//Returns path of local players' profiles: <user config dir>/seabattle/profiles.json
+func profilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "seabattle", "profiles.json")
+}
+
+//Reads profiles of local players. Missing store is empty
+func readProfiles() (*profileStore, error) {
+	store := &profileStore{Profiles: make(map[string]*profile.Profile)}
+
+	data, err := os.ReadFile(profilesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+	if store.Profiles == nil {
+		store.Profiles = make(map[string]*profile.Profile)
+	}
+
+	return store, nil
+}
+
+//Writes profiles of local players
+func writeProfiles(store *profileStore) error {
+	path := profilesPath()
+	if path == "" {
+		return errors.New("save profiles: user config directory is unknown")
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("save profiles: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+//Returns sorted names of local players
+func (store *profileStore) names() []string {
+	names := make([]string, 0, len(store.Profiles))
+	for name := range store.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//Returns profile of player, which is created if player has none
+func (store *profileStore) profile(name string) *profile.Profile {
+	p, ok := store.Profiles[name]
+	if !ok {
+		p = profile.New(name)
+		store.Profiles[name] = p
+	}
+
+	return p
+}
+
+//Adds result of finished game to user's profile. Rating is requested from remote server
+//in background, because server may need some time to update it. Errors are only printed,
+//because profile is not needed to finish the game
+func updateProfile() {
+	if gameRecord == nil || len(gameRecord.Moves) == 0 || gameRecord.User == "" {
+		return
+	}
+	result := profile.NewResult(gameRecord, botBoard.FleetDestroyed())
+	username := gameRecord.User
+
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	store, err := readProfiles()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	store.profile(username).Add(result)
+	store.Last = username
+	if err := writeProfiles(store); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if client, ok := backend.(*api.Client); ok {
+		go updateRating(client, username, result.GameID)
+	}
+}
+
+//Requests rating of player from server and stores it in player's profile.
+//Servers which don't rate players are ignored
+func updateRating(client *api.Client, username string, gameID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), ratingTimeout)
+	defer cancel()
+
+	rating, err := client.Rating(ctx, username)
+	if api.IsUnsupported(err) {
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	store, err := readProfiles()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	store.profile(username).SetRating(gameID, rating.Rating, rating.Deviation)
+	if err := writeProfiles(store); err != nil {
+		fmt.Println(err)
+	}
+}
+
+//Returns names of local players and name of player who played the last game
+func profileNames() ([]string, string) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	store, err := readProfiles()
+	if err != nil {
+		fmt.Println(err)
+		return nil, ""
+	}
+
+	return store.names(), store.Last
+}
+
+//Initializes profile container, which shows lifetime statistics of player
+//and charts of accuracy, shots per win and rating over time
+func newProfileContainer(window fyne.Window, name string) {
+	profilesMu.Lock()
+	store, err := readProfiles()
+	profilesMu.Unlock()
+	if err != nil {
+		showError(err)
+		return
+	}
+	p, ok := store.Profiles[name]
+	if !ok {
+		p = profile.New(name)
+	}
+	summary := p.Summary()
+
+	titleLabel := widget.NewLabelWithStyle("Profile of "+name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	titleLabel.Move(fyne.NewPos(30, 15))
+
+	rating := "not rated"
+	if p.Rating != 0 {
+		rating = fmt.Sprintf("%.0f", p.Rating)
+		if p.Deviation != 0 {
+			rating += fmt.Sprintf(" ± %.0f", p.Deviation)
+		}
+	}
+	summaryGrid := container.NewGridWithColumns(4,
+		widget.NewLabel(fmt.Sprintf("Games: %d", summary.Games)),
+		widget.NewLabel(fmt.Sprintf("Wins: %d", summary.Wins)),
+		widget.NewLabel(fmt.Sprintf("Losses: %d", summary.Losses)),
+		widget.NewLabel(fmt.Sprintf("Win rate: %.0f%%", 100*summary.WinRate)),
+		widget.NewLabel(fmt.Sprintf("Shots per win: %.1f", summary.ShotsPerWin)),
+		widget.NewLabel(fmt.Sprintf("Accuracy: %.0f%%", 100*summary.Accuracy)),
+		widget.NewLabel("Rating: "+rating),
+	)
+	summaryGrid.Resize(fyne.NewSize(640, summaryGrid.MinSize().Height))
+	summaryGrid.Move(fyne.NewPos(30, 55))
+
+	percent := func(v float64) string { return fmt.Sprintf("%.0f%%", 100*v) }
+	number := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	accuracy, average := p.AccuracyTrend(accuracyTrendWindow)
+	chartSize := fyne.NewSize(170, 200)
+	charts := []*fyne.Container{
+		newLineChart("Accuracy", accuracy, average, percent, chartSize),
+		newLineChart("Shots per win", p.ShotsPerWinHistory(), nil, number, chartSize),
+		newLineChart("Rating", p.RatingHistory(), nil, number, chartSize),
+	}
+	for i, chart := range charts {
+		chart.Move(fyne.NewPos(30+220*float32(i), 150))
+	}
+
+	backButton := widget.NewButton("Back", func() {
+		newMainContainer(window)
+	})
+	backButton.Move(fyne.NewPos(window.Canvas().Size().Width-150, window.Canvas().Size().Height-60))
+	backButton.Resize(fyne.NewSize(100, 45))
+
+	profileContainer := container.NewWithoutLayout(titleLabel, summaryGrid, backButton)
+	for _, chart := range charts {
+		profileContainer.Add(chart)
+	}
+	profileContainer.Refresh()
+	window.SetContent(profileContainer)
+
+	window.SetTitle("Sea Battle: Profile of " + name)
+}
@@ -0,0 +1,209 @@
+//Package record keeps history of game moves, exports it as JSON and rebuilds
+//boards of both players after any move, so finished games can be replayed
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"client.go/engine"
+	"client.go/fairplay"
+)
+
+//Version of exported record format
+const Version = 1
+
+//Players of Move.Player
+const (
+	User     = "user"     //player who recorded the game
+	Opponent = "opponent" //bot or another human player
+)
+
+//Move is one shot of game
+type Move struct {
+	Player string //User or Opponent
+	X      int
+	Y      int
+	Result string //"miss", "hit" or "kill"
+	Time   time.Time
+}
+
+//GameRecord contains rules, fleets and all moves of one game from the point of view of user
+type GameRecord struct {
+	Version  int
+	GameID   string
+	User     string //name of user
+	Opponent string //name of opponent
+	Rules    engine.Rules
+	Seed     int64 `json:",omitempty"` //seed of game's random decisions
+	Started  time.Time
+	Finished time.Time `json:",omitempty"`
+	//user's fleet and opponent's fleet, which is known only if opponent revealed it
+	UserFleet     []engine.Ship
+	OpponentFleet []engine.Ship `json:",omitempty"`
+	Moves         []Move
+}
+
+//Creates record of new game. User's fleet is copied, so later hits don't change it
+func New(gameID string, rules engine.Rules, userFleet []engine.Ship) *GameRecord {
+	return &GameRecord{
+		Version:   Version,
+		GameID:    gameID,
+		Rules:     rules,
+		Started:   time.Now(),
+		UserFleet: freshShips(userFleet),
+	}
+}
+
+//Adds shot of given player to record
+func (r *GameRecord) Add(player string, p engine.Point, result engine.ShotResult) {
+	r.Moves = append(r.Moves, Move{Player: player, X: p.X, Y: p.Y, Result: result.String(), Time: time.Now()})
+}
+
+//Returns shots of given player with their results, in the form used by fair play audit
+func (r *GameRecord) Shots(player string) []fairplay.Shot {
+	var shots []fairplay.Shot
+
+	for _, move := range r.Moves {
+		if move.Player != player {
+			continue
+		}
+		if result, err := engine.ParseShotResult(move.Result); err == nil {
+			shots = append(shots, fairplay.Shot{X: move.X, Y: move.Y, Result: result})
+		}
+	}
+
+	return shots
+}
+
+//Returns user's board and opponent's board after first n moves. User's board contains
+//user's fleet. Opponent's board contains opponent's fleet if it is known, otherwise
+//it contains only results of user's shots. Record should pass Validate
+func (r *GameRecord) BoardsAt(n int) (*engine.Board, *engine.Board, error) {
+	user, err := fleetBoard(r.Rules, r.UserFleet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user's fleet: %w", err)
+	}
+
+	opponent := engine.NewBoard(r.Rules)
+	if r.OpponentFleet != nil {
+		if opponent, err = fleetBoard(r.Rules, r.OpponentFleet); err != nil {
+			return nil, nil, fmt.Errorf("opponent's fleet: %w", err)
+		}
+	}
+
+	if n > len(r.Moves) {
+		n = len(r.Moves)
+	}
+	for i, move := range r.Moves[:n] {
+		p := engine.Point{X: move.X, Y: move.Y}
+		result, err := engine.ParseShotResult(move.Result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+
+		switch {
+		case move.Player == Opponent:
+			_, err = user.Receive(p)
+		case r.OpponentFleet != nil:
+			_, err = opponent.Receive(p)
+		default:
+			err = opponent.Mark(p, result)
+		}
+		//shot of salvo may hit cell covered around ship killed by previous shot of the same salvo
+		if err != nil && !errors.Is(err, engine.ErrAlreadyShot) {
+			return nil, nil, fmt.Errorf("move %d: shot %d:%d: %w", i+1, move.X, move.Y, err)
+		}
+	}
+
+	return user, opponent, nil
+}
+
+//Returns error if record can't be replayed: its rules are invalid, ships don't
+//describe ships of rules or moves are made by unknown player or out of board.
+//Record is read from file, so it is checked before boards are built from it
+func (r *GameRecord) Validate() error {
+	if err := r.Rules.Validate(); err != nil {
+		return err
+	}
+
+	fleets := map[string][]engine.Ship{"user's fleet": r.UserFleet, "opponent's fleet": r.OpponentFleet}
+	for name, ships := range fleets {
+		for i, ship := range ships {
+			if err := r.Rules.CheckShip(ship); err != nil {
+				return fmt.Errorf("%s: ship %d: %w", name, i+1, err)
+			}
+		}
+	}
+
+	board := engine.NewBoard(r.Rules)
+	for i, move := range r.Moves {
+		if move.Player != User && move.Player != Opponent {
+			return fmt.Errorf("move %d: unknown player %q", i+1, move.Player)
+		}
+		if !board.InBounds(engine.Point{X: move.X, Y: move.Y}) {
+			return fmt.Errorf("move %d: shot %d:%d: %w", i+1, move.X, move.Y, engine.ErrOutOfBoard)
+		}
+		if _, err := engine.ParseShotResult(move.Result); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+//Writes record as indented JSON
+func (r *GameRecord) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r)
+}
+
+//Reads record written by WriteJSON. Record may come from anywhere, so it is checked by Validate
+func ReadJSON(reader io.Reader) (*GameRecord, error) {
+	var r GameRecord
+
+	if err := json.NewDecoder(reader).Decode(&r); err != nil {
+		return nil, fmt.Errorf("read game record: %w", err)
+	}
+	if r.Version != Version {
+		return nil, fmt.Errorf("game record has unsupported version %d", r.Version)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("game record: %w", err)
+	}
+
+	return &r, nil
+}
+
+//Returns new board with given fleet. Ships are checked first, because they may come from file
+func fleetBoard(rules engine.Rules, ships []engine.Ship) (*engine.Board, error) {
+	board := engine.NewBoard(rules)
+
+	for i, ship := range ships {
+		if err := rules.CheckShip(ship); err != nil {
+			return nil, fmt.Errorf("ship %d: %w", i+1, err)
+		}
+	}
+	for _, ship := range freshShips(ships) {
+		if err := board.Place(ship); err != nil {
+			return nil, err
+		}
+	}
+
+	return board, nil
+}
+
+//Returns copies of ships with all decks alive
+func freshShips(ships []engine.Ship) []engine.Ship {
+	fresh := make([]engine.Ship, 0, len(ships))
+	for _, ship := range ships {
+		fresh = append(fresh, engine.NewShapedShip(ship.Offsets(), ship.BaseDeckPosition))
+	}
+
+	return fresh
+}
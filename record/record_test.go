@@ -0,0 +1,170 @@
+package record
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"client.go/engine"
+)
+
+//Returns finished record of game by given rules with both fleets known
+func testRecord(t *testing.T, rules engine.Rules) *GameRecord {
+	t.Helper()
+
+	r := rand.New(rand.NewSource(3))
+	user, opponent := engine.NewBoard(rules), engine.NewBoard(rules)
+	for _, board := range []*engine.Board{user, opponent} {
+		if err := board.PlaceRandomly(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := New("game-1", rules, user.Fleet.Array)
+	record.User, record.Opponent, record.Seed = "alice", "bot", 42
+	record.OpponentFleet = freshShips(opponent.Fleet.Array)
+
+	//players shoot row by row in turn until user sinks the whole fleet
+	for _, p := range opponent.Points() {
+		if result, err := opponent.Receive(p); err == nil {
+			record.Add(User, p, result)
+		}
+		if result, err := user.Receive(p); err == nil {
+			record.Add(Opponent, p, result)
+		}
+		if opponent.FleetDestroyed() {
+			break
+		}
+	}
+	record.Finished = time.Now()
+
+	return record
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, rules := range []engine.Rules{engine.Classic, engine.Polyomino} {
+		t.Run(rules.Name, func(t *testing.T) {
+			record := testRecord(t, rules)
+
+			var first bytes.Buffer
+			if err := record.WriteJSON(&first); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadJSON(bytes.NewReader(first.Bytes()))
+			if err != nil {
+				t.Fatalf("ReadJSON() = %v", err)
+			}
+			var second bytes.Buffer
+			if err := read.WriteJSON(&second); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first.Bytes(), second.Bytes()) {
+				t.Fatalf("record changed after round trip:\n%s\n%s", first.Bytes(), second.Bytes())
+			}
+
+			if !read.Started.Equal(record.Started) || !read.Finished.Equal(record.Finished) {
+				t.Errorf("times are %v - %v, want %v - %v", read.Started, read.Finished, record.Started, record.Finished)
+			}
+			if !reflect.DeepEqual(read.Shots(User), record.Shots(User)) ||
+				!reflect.DeepEqual(read.Shots(Opponent), record.Shots(Opponent)) {
+				t.Errorf("shots changed after round trip")
+			}
+
+			//replay of read record ends with the same boards
+			wantUser, wantOpponent, err := record.BoardsAt(len(record.Moves))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotUser, gotOpponent, err := read.BoardsAt(len(read.Moves))
+			if err != nil {
+				t.Fatalf("BoardsAt() = %v", err)
+			}
+			if !reflect.DeepEqual(gotUser.Layout(), wantUser.Layout()) ||
+				!reflect.DeepEqual(gotOpponent.Layout(), wantOpponent.Layout()) {
+				t.Errorf("replayed fleets differ after round trip")
+			}
+			for _, p := range gotOpponent.Points() {
+				if gotUser.At(p) != wantUser.At(p) || gotOpponent.At(p) != wantOpponent.At(p) {
+					t.Fatalf("cell %v differs after round trip", p)
+				}
+			}
+			if !gotOpponent.FleetDestroyed() {
+				t.Errorf("opponent's fleet isn't destroyed in the end of replay")
+			}
+		})
+	}
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", "moves", "read game record"},
+		{"other version", `{"Version": 2}`, "unsupported version 2"},
+		{"no version", `{"GameID": "game-1"}`, "unsupported version 0"},
+	}
+
+	//records which are valid JSON of current version, but can't be replayed
+	invalid := []struct {
+		name   string
+		change func(r *GameRecord)
+		want   string
+	}{
+		{"invalid rules", func(r *GameRecord) { r.Rules.Width = 0 }, "board size"},
+		{"move out of board", func(r *GameRecord) { r.Moves[0].X = r.Rules.Height }, "out of board"},
+		{"negative move", func(r *GameRecord) { r.Moves[1].Y = -1 }, "out of board"},
+		{"unknown player", func(r *GameRecord) { r.Moves[0].Player = "spectator" }, "unknown player"},
+		{"unknown result", func(r *GameRecord) { r.Moves[0].Result = "sunk" }, "unknown shot result"},
+		{"ship of negative size", func(r *GameRecord) { r.UserFleet[0].Size = -1 }, "invalid ship"},
+		{"opponent's ship too long", func(r *GameRecord) { r.OpponentFleet[0].Size = 11 }, "invalid ship"},
+	}
+	for _, tt := range invalid {
+		record := testRecord(t, engine.Classic)
+		tt.change(record)
+		var buffer bytes.Buffer
+		if err := record.WriteJSON(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		tests = append(tests, struct {
+			name string
+			data string
+			want string
+		}{tt.name, buffer.String(), tt.want})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJSON(strings.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadJSON() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoardsAtReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(r *GameRecord)
+	}{
+		{"move out of board", func(r *GameRecord) { r.Moves[0].X = -1 }},
+		{"user's ship of negative size", func(r *GameRecord) { r.UserFleet[0].Size = -1 }},
+		{"opponent's ships collide", func(r *GameRecord) { r.OpponentFleet[1] = r.OpponentFleet[0] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := testRecord(t, engine.Classic)
+			tt.change(record)
+
+			if _, _, err := record.BoardsAt(len(record.Moves)); err == nil {
+				t.Errorf("BoardsAt() returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package record
+
+import (
+	"time"
+
+	"client.go/engine"
+)
+
+//Stats summarizes shots of one player
+type Stats struct {
+	Shots         int
+	Hits          int //hits and kills
+	Kills         int
+	Accuracy      float64 //part of shots which hit a ship, from 0 to 1
+	LongestStreak int     //longest series of player's shots which hit one after another
+}
+
+//Returns statistics of given player's shots
+func (r *GameRecord) Stats(player string) Stats {
+	var stats Stats
+	streak := 0
+
+	for _, move := range r.Moves {
+		if move.Player != player {
+			continue
+		}
+		stats.Shots++
+
+		result, _ := engine.ParseShotResult(move.Result)
+		if result == engine.ShotMiss {
+			streak = 0
+			continue
+		}
+
+		stats.Hits++
+		if result == engine.ShotKill {
+			stats.Kills++
+		}
+		if streak++; streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+	}
+
+	if stats.Shots > 0 {
+		stats.Accuracy = float64(stats.Hits) / float64(stats.Shots)
+	}
+
+	return stats
+}
+
+//Returns time from start to end of game, or to current moment if game is not finished
+func (r *GameRecord) Duration() time.Duration {
+	if r.Finished.IsZero() {
+		return time.Since(r.Started)
+	}
+
+	return r.Finished.Sub(r.Started)
+}
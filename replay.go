@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"client.go/record"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Delay between moves when replay is playing
+const replayDelay = 700 * time.Millisecond
+
+var gameRecord *record.GameRecord //history of moves of current game
+
+//Returns directory of records of finished games: <user config dir>/seabattle/records
+func recordsDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "seabattle", "records")
+}
+
+//Completes record of current game and stores it in records directory.
+//Errors are only printed, because record is not needed to finish the game
+func finishRecord() {
+	if gameRecord == nil || len(gameRecord.Moves) == 0 {
+		return
+	}
+
+	username, opponent := playerNames()
+	gameRecord.User, gameRecord.Opponent = username, opponent
+	gameRecord.Finished = time.Now()
+
+	dir := recordsDir()
+	if dir == "" {
+		fmt.Println("save game record: user config directory is unknown")
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s.json", gameRecord.Started.Format("20060102-150405"), gameRecord.GameID)
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer file.Close()
+
+	if err := gameRecord.WriteJSON(file); err != nil {
+		fmt.Println(err)
+	}
+}
+
+//Lets user choose file and writes record of current game to it
+func exportRecord(window fyne.Window) {
+	rec := gameRecord
+	if rec == nil {
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			showError(err)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if err := rec.WriteJSON(writer); err != nil {
+			showError(err)
+		}
+	}, window)
+	saveDialog.SetFileName(rec.GameID + ".json")
+	saveDialog.Show()
+}
+
+//Lets user choose record of game and opens replay of it
+func openReplay(window fyne.Window) {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			showError(err)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		//record is validated while it is read, so moves and ships fit its rules
+		rec, err := record.ReadJSON(reader)
+		if err != nil {
+			showError(err)
+			return
+		}
+		newReplayContainer(window, rec)
+	}, window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	if dir := recordsDir(); dir != "" {
+		if location, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			openDialog.SetLocation(location)
+		}
+	}
+	openDialog.Show()
+}
+
+//Initializes replay container, which shows both fields of recorded game after chosen move.
+//Moves are played automatically or stepped one by one, slider seeks to any move
+func newReplayContainer(window fyne.Window, rec *record.GameRecord) {
+	fieldSize := window.Canvas().Size().Width/2 - 75
+	rules := rec.Rules
+	size := cellSize(rules, fieldSize)
+
+	userContainer := container.NewGridWithColumns(rules.Width)
+	opponentContainer := container.NewGridWithColumns(rules.Width)
+
+	userContainer.Move(fyne.NewPos(50, 60))
+	opponentContainer.Move(fyne.NewPos(fieldSize+100, 60))
+	userContainer.Resize(fyne.NewSize(size*float32(rules.Width), size*float32(rules.Height)))
+	opponentContainer.Resize(fyne.NewSize(size*float32(rules.Width), size*float32(rules.Height)))
+
+	userLabel := widget.NewLabel(rec.User + "'s field:")
+	opponentLabel := widget.NewLabel(rec.Opponent + "'s field:")
+	userLabel.Move(fyne.NewPos(50, 15))
+	opponentLabel.Move(fyne.NewPos(fieldSize+100, 15))
+
+	moveLabel := widget.NewLabel("")
+	moveLabel.Move(fyne.NewPos(50, window.Canvas().Size().Height-140))
+
+	userCells := setButtons(userContainer, rules, "", nil)
+	opponentCells := setButtons(opponentContainer, rules, "", nil)
+
+	var mu sync.Mutex
+	position := 0
+	stopPlaying := func() {}
+
+	//Renders both fields after first n moves and highlights the last one
+	show := func(n int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		user, opponent, err := rec.BoardsAt(n)
+		if err != nil {
+			showError(err)
+			return
+		}
+		position = n
+		renderBoard(userCells, user)
+		renderBoard(opponentCells, opponent)
+
+		if n == 0 {
+			moveLabel.SetText(fmt.Sprintf("Move 0 of %d", len(rec.Moves)))
+			return
+		}
+
+		move := rec.Moves[n-1]
+		cells, name := opponentCells, rec.User
+		if move.Player == record.Opponent {
+			cells, name = userCells, rec.Opponent
+		}
+		button := cells[move.X][move.Y].Button
+		button.Importance = widget.HighImportance
+		button.Refresh()
+
+		moveLabel.SetText(fmt.Sprintf("Move %d of %d: %s shot %d:%d - %s",
+			n, len(rec.Moves), name, move.X, move.Y, move.Result))
+	}
+
+	slider := widget.NewSlider(0, float64(len(rec.Moves)))
+	slider.Step = 1
+	slider.OnChanged = func(value float64) {
+		show(int(value))
+	}
+	slider.Move(fyne.NewPos(50, window.Canvas().Size().Height-100))
+	slider.Resize(fyne.NewSize(window.Canvas().Size().Width-100, slider.MinSize().Height))
+
+	//Moves slider by given number of moves, which renders the fields
+	step := func(delta int) bool {
+		mu.Lock()
+		next := position + delta
+		mu.Unlock()
+
+		if next < 0 || next > len(rec.Moves) {
+			return false
+		}
+		slider.SetValue(float64(next))
+
+		return true
+	}
+
+	var playButton *widget.Button
+	playButton = widget.NewButton("Play", func() {
+		if playButton.Text == "Pause" {
+			stopPlaying()
+			return
+		}
+
+		//replay which reached the end starts from the beginning
+		mu.Lock()
+		finished := position == len(rec.Moves)
+		mu.Unlock()
+		if finished {
+			slider.SetValue(0)
+		}
+
+		done := make(chan struct{})
+		var once sync.Once
+		stop := func() {
+			once.Do(func() {
+				close(done)
+				playButton.SetText("Play")
+			})
+		}
+		stopPlaying = stop
+		playButton.SetText("Pause")
+
+		go func() {
+			ticker := time.NewTicker(replayDelay)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if !step(1) {
+						stop()
+						return
+					}
+				}
+			}
+		}()
+	})
+
+	stepBackButton := widget.NewButton("<", func() {
+		stopPlaying()
+		step(-1)
+	})
+	stepButton := widget.NewButton(">", func() {
+		stopPlaying()
+		step(1)
+	})
+	backButton := widget.NewButton("Back", func() {
+		stopPlaying()
+		newMainContainer(window)
+	})
+
+	center := window.Canvas().Size().Width / 2
+	buttonsY := window.Canvas().Size().Height - 60
+	stepBackButton.Move(fyne.NewPos(center-160, buttonsY))
+	playButton.Move(fyne.NewPos(center-50, buttonsY))
+	stepButton.Move(fyne.NewPos(center+60, buttonsY))
+	backButton.Move(fyne.NewPos(window.Canvas().Size().Width-150, buttonsY))
+	for _, button := range []*widget.Button{stepBackButton, playButton, stepButton, backButton} {
+		button.Resize(fyne.NewSize(100, 45))
+	}
+
+	show(0)
+
+	replayContainer := container.NewWithoutLayout(
+		userLabel,
+		opponentLabel,
+		userContainer,
+		opponentContainer,
+		moveLabel,
+		slider,
+		stepBackButton,
+		playButton,
+		stepButton,
+		backButton,
+	)
+	replayContainer.Refresh()
+	window.SetContent(replayContainer)
+
+	window.SetTitle("Sea Battle: Replay of game " + rec.GameID)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strconv"
+
+	"client.go/engine"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Option of rules select which opens form of custom rules
+const customRulesOption = "Custom..."
+
+//Returns names of preset rule sets followed by custom option
+func rulesOptions() []string {
+	return append(presetNames(), customRulesOption)
+}
+
+//Returns names of ship types of rules, from the biggest ship to the smallest
+func shipNames(rules engine.Rules) []string {
+	names := make([]string, 0, len(rules.Fleet))
+	for _, spec := range rules.Fleet {
+		names = append(names, spec.Name)
+	}
+
+	return names
+}
+
+//Returns ship type with given name in rules of user's field
+func shipSpec(name string) (engine.ShipSpec, bool) {
+	for _, spec := range userBoard.Rules.Fleet {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+
+	return engine.ShipSpec{}, false
+}
+
+//Asks user for board size and fleet of custom rules. 'apply' is called only with valid rules
+func showCustomRulesForm(window fyne.Window, apply func(engine.Rules), cancel func()) {
+	widthEntry := widget.NewEntry()
+	widthEntry.SetText(strconv.Itoa(userBoard.Rules.Width))
+	heightEntry := widget.NewEntry()
+	heightEntry.SetText(strconv.Itoa(userBoard.Rules.Height))
+	fleetEntry := widget.NewEntry()
+	fleetEntry.SetPlaceHolder("5,4,3,3,2 or L,T,O,3,2")
+	adjacencySelect := widget.NewSelect(engine.AdjacencyNames[:], nil)
+	adjacencySelect.SetSelected(userBoard.Rules.Adjacency.String())
+	salvoEntry := widget.NewEntry()
+	salvoEntry.SetPlaceHolder("1, or \"ships\" for one per surviving ship")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Width", widthEntry),
+		widget.NewFormItem("Height", heightEntry),
+		widget.NewFormItem("Ship sizes", fleetEntry),
+		widget.NewFormItem("Ships may touch", adjacencySelect),
+		widget.NewFormItem("Shots per turn", salvoEntry),
+	}
+	dialog.ShowForm("Custom rules", "Apply", "Cancel", items, func(ok bool) {
+		if !ok {
+			cancel()
+			return
+		}
+
+		rules, err := parseCustomRules(widthEntry.Text, heightEntry.Text, fleetEntry.Text, adjacencySelect.Selected, salvoEntry.Text)
+		if err != nil {
+			showError(err)
+			cancel()
+			return
+		}
+		apply(rules)
+	}, window)
+}
+
+//Builds custom rules from text entered by user
+func parseCustomRules(width string, height string, fleet string, adjacency string, salvo string) (engine.Rules, error) {
+	w, err := strconv.Atoi(width)
+	if err != nil {
+		return engine.Rules{}, err
+	}
+	h, err := strconv.Atoi(height)
+	if err != nil {
+		return engine.Rules{}, err
+	}
+	ships, err := engine.ParseFleet(fleet)
+	if err != nil {
+		return engine.Rules{}, err
+	}
+	a, err := engine.ParseAdjacency(adjacency)
+	if err != nil {
+		return engine.Rules{}, err
+	}
+
+	shots, err := engine.ParseSalvo(salvo)
+	if err != nil {
+		return engine.Rules{}, err
+	}
+
+	return engine.CustomRules(w, h, ships, a, shots)
+}
+
+//Returns size of square field cell, so field of given rules fits into box of given size
+func cellSize(rules engine.Rules, box float32) float32 {
+	longest := rules.Width
+	if rules.Height > longest {
+		longest = rules.Height
+	}
+
+	return box / float32(longest)
+}
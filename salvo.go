@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"client.go/api"
+	"client.go/engine"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Cells of bot's field selected for salvo of current turn
+var salvoTargets []engine.Point
+
+//Returns number of shots user fires in one turn, it depends on surviving ships in some rules
+func salvoSize() int {
+	return userBoard.Rules.ShotsPerTurn(userBoard.Fleet)
+}
+
+//Selects cell of bot's field for salvo, or deselects it if it was selected already. Caller must hold gameMu
+func toggleSalvoTarget(cell Cell) {
+	p := cell.point()
+
+	for i, target := range salvoTargets {
+		if target == p {
+			salvoTargets = append(salvoTargets[:i:i], salvoTargets[i+1:]...)
+			renderBotField()
+			return
+		}
+	}
+
+	if botBoard.At(p) != engine.CellEmpty {
+		fmt.Println("\nYou were shooting this cell already")
+		return
+	}
+	if len(salvoTargets) >= salvoSize() {
+		fmt.Println("\nAll shots of salvo are selected")
+		return
+	}
+
+	salvoTargets = append(salvoTargets, p)
+	renderBotField()
+}
+
+//Renders bot's field, highlights cells selected for salvo with "o" and updates
+//sidebar with opponent's fleet and heatmap overlay. Caller must hold gameMu
+func renderBotField() {
+	renderBoard(botCellArray, botBoard)
+	updateFleetTracker()
+	updateHeatmap()
+
+	for _, p := range salvoTargets {
+		button := botCellArray[p.X][p.Y].Button
+		button.Importance = widget.HighImportance
+		button.SetText("o")
+	}
+}
+
+//Sends salvo selected by user to backend and applies results of both user's and bot's salvos
+func fireSalvo() error {
+	if len(salvoTargets) == 0 {
+		return errors.New("select cells of salvo on opponent's field first")
+	}
+
+	targets := make([]api.Shot, 0, len(salvoTargets))
+	for _, p := range salvoTargets {
+		targets = append(targets, api.Shot{X: p.X, Y: p.Y})
+	}
+
+	game, err := backend.ShootSalvo(context.Background(), gameData.GameID, targets)
+	if err != nil {
+		return err
+	}
+	salvoTargets = nil
+	gameData = game
+	analyzeResponse()
+	applyBotShots(gameData.BotShots)
+
+	return nil
+}
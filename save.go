@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"client.go/api"
+	"client.go/engine"
+	"client.go/local"
+	"client.go/record"
+	"client.go/strategy"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+//Version of saved game format. Files of other versions are not resumed
+const saveVersion = 3
+
+//SavedGame contains everything needed to resume current game after client restarts
+type SavedGame struct {
+	Version    int
+	SavedAt    time.Time
+	Server     string //address of remote server, empty in offline games
+	Room       bool   //true for multiplayer rooms
+	Game       api.GameData
+	UserBoard  *engine.Board
+	BotBoard   *engine.Board
+	Salt       string             //hex encoded salt of user's fleet commitment
+	Commitment string             //commitment of user's fleet sent to opponent
+	Record     *record.GameRecord //history of moves
+	//state of offline game, which is kept by local bot
+	Strategy string           `json:",omitempty"`
+	Offline  *local.GameState `json:",omitempty"`
+}
+
+var offlineStrategy string //name of local bot's strategy in current offline game
+var isRoom bool            //true if current game is multiplayer room
+
+//Returns path of saved game: <user config dir>/seabattle/savegame.json
+func savePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "seabattle", "savegame.json")
+}
+
+//Saves state of current game. Finished games are not saved. Errors are only printed,
+//because game can go on without autosave
+func autosave() {
+	if gameData.GameID == "" || gameFinished {
+		return
+	}
+
+	saved := SavedGame{
+		Version:    saveVersion,
+		SavedAt:    time.Now(),
+		Room:       isRoom,
+		Game:       gameData,
+		UserBoard:  userBoard,
+		BotBoard:   botBoard,
+		Salt:       hex.EncodeToString(fleetSalt),
+		Commitment: fleetCommitment,
+		Record:     gameRecord,
+	}
+
+	switch b := backend.(type) {
+	case *api.Client:
+		saved.Server = b.BaseURL
+	case *local.Server:
+		state, err := b.State(gameData.GameID)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		saved.Strategy = offlineStrategy
+		saved.Offline = &state
+	}
+
+	if err := writeSave(savePath(), saved); err != nil {
+		fmt.Println(err)
+	}
+}
+
+//Writes saved game to file. Data is written to temporary file first,
+//so previous save is not lost if writing fails
+func writeSave(path string, saved SavedGame) error {
+	if path == "" {
+		return errors.New("save game: user config directory is unknown")
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("save game: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("save game: %w", err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o600); err != nil {
+		return fmt.Errorf("save game: %w", err)
+	}
+
+	return os.Rename(temp, path)
+}
+
+//Reads saved game. Returns os.ErrNotExist if there is no saved game
+func readSave(path string) (SavedGame, error) {
+	var saved SavedGame
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return saved, err
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return saved, fmt.Errorf("read saved game: %w", err)
+	}
+	if saved.Version != saveVersion {
+		return saved, fmt.Errorf("saved game has unsupported version %d", saved.Version)
+	}
+	if saved.UserBoard == nil || saved.BotBoard == nil || saved.Record == nil || saved.Game.GameID == "" {
+		return saved, errors.New("saved game is incomplete")
+	}
+
+	return saved, nil
+}
+
+//Deletes saved game, it is called when game ends
+func removeSave() {
+	if err := os.Remove(savePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Println(err)
+	}
+}
+
+//Offers user to resume saved game, if there is one
+func offerResume(window fyne.Window) {
+	saved, err := readSave(savePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		removeSave()
+		return
+	}
+
+	message := fmt.Sprintf("Resume game %s saved at %s?", saved.Game.GameID, saved.SavedAt.Format("2006-01-02 15:04"))
+	dialog.ShowConfirm("Resume last game", message, func(ok bool) {
+		if !ok {
+			removeSave()
+			return
+		}
+
+		if err := resumeGame(window, saved); err != nil {
+			removeSave()
+			showError(err)
+		}
+	}, window)
+}
+
+//Restores saved game and opens game container. Remote game is resumed only if server still has its session
+func resumeGame(window fyne.Window, saved SavedGame) error {
+	salt, err := hex.DecodeString(saved.Salt)
+	if err != nil {
+		return fmt.Errorf("saved game: %w", err)
+	}
+
+	var game api.GameData
+	var client *api.Client
+
+	if saved.Offline != nil {
+		//bot's shots are drawn from seed of the game, as in new game
+		bot, err := strategy.New(saved.Strategy, seededRand(saved.Offline.Data.Seed, local.BotShots))
+		if err != nil {
+			return err
+		}
+		server := local.NewServer(bot)
+		if err := server.Restore(*saved.Offline); err != nil {
+			return err
+		}
+		backend = server
+		game = saved.Game
+	} else {
+		client = api.NewClient(saved.Server)
+		client.Player = saved.Game.Player
+
+		ctx, cancel := context.WithTimeout(context.Background(), api.DefaultTimeout)
+		defer cancel()
+
+		if saved.Room {
+			game, err = client.RoomState(ctx, saved.Game.GameID)
+		} else {
+			game, err = client.GameState(ctx, saved.Game.GameID)
+		}
+		if err != nil {
+			return fmt.Errorf("game %s can't be resumed: %w", saved.Game.GameID, err)
+		}
+		backend = client
+	}
+
+	gameMu.Lock()
+	defer gameMu.Unlock()
+
+	userBoard, botBoard = saved.UserBoard, saved.BotBoard
+	fleetSalt, fleetCommitment, gameRecord = salt, saved.Commitment, saved.Record
+	offlineStrategy, isRoom = saved.Strategy, saved.Room
+	gameData = saved.Game
+	gameFinished = false
+	newGameContainer(window)
+
+	//server could go on while client was closed, e.g. opponent shot in multiplayer room
+	applyGameState(context.Background(), game)
+
+	if client != nil && !gameFinished {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopGame = cancel
+		go watchGame(ctx, client, game.GameID, saved.Room)
+	}
+
+	return nil
+}
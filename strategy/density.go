@@ -0,0 +1,105 @@
+package strategy
+
+import (
+	"math/rand"
+
+	"client.go/engine"
+)
+
+//hitWeight multiplies density of placements which cover wounded ship's decks,
+//so such placements are checked before hunting continues. Weights are floats, because
+//placement of long wounded ship is weighted by power of hitWeight, which overflows int
+const hitWeight = 100.0
+
+//ProbabilityDensity counts, for every cell, how many placements of remaining ships
+//cover it, and shoots the cell with the highest count. Remaining ships are taken
+//from fleet of board's rules without ships which are sunk already
+type ProbabilityDensity struct {
+	rand *rand.Rand
+}
+
+//Creates new probability-density strategy
+func NewProbabilityDensity(r *rand.Rand) *ProbabilityDensity {
+	return &ProbabilityDensity{rand: r}
+}
+
+//Returns empty cell with the highest density. Ties are broken randomly
+func (s *ProbabilityDensity) NextShot(board *engine.Board) engine.Point {
+	density := Density(board)
+
+	var best []engine.Point
+	bestValue := -1.0
+
+	for _, p := range board.Points() {
+		if board.At(p) != engine.CellEmpty {
+			continue
+		}
+
+		switch value := density[p.X][p.Y]; {
+		case value > bestValue:
+			bestValue = value
+			best = []engine.Point{p}
+		case value == bestValue:
+			best = append(best, p)
+		}
+	}
+
+	return pick(s.rand, best)
+}
+
+//Returns, for every cell, weighted number of placements of remaining ships which cover it.
+//Placements can't cover missed cells or sunk ships; placements which cover hit decks
+//of wounded ships are weighted by hitWeight for each covered hit
+func Density(board *engine.Board) [][]float64 {
+	density := make([][]float64, board.Rules.Height)
+	for x := range density {
+		density[x] = make([]float64, board.Rules.Width)
+	}
+
+	for _, ships := range remainingFleet(board) {
+		for _, shape := range ships.variants {
+			for _, base := range board.Points() {
+				cells := make([]engine.Point, 0, len(shape))
+				for _, offset := range shape {
+					cells = append(cells, engine.Point{X: base.X + offset.X, Y: base.Y + offset.Y})
+				}
+
+				weight, ok := placementWeight(board, cells)
+				if !ok {
+					continue
+				}
+
+				for _, p := range cells {
+					if board.At(p) == engine.CellEmpty {
+						density[p.X][p.Y] += weight * float64(ships.count)
+					}
+				}
+			}
+		}
+	}
+
+	return density
+}
+
+//Returns weight of placement of ship's decks and false if ship can't be located there
+func placementWeight(board *engine.Board, cells []engine.Point) (float64, bool) {
+	weight := 1.0
+
+	for _, p := range cells {
+		if !board.InBounds(p) {
+			return 0, false
+		}
+
+		switch board.At(p) {
+		case engine.CellMiss:
+			return 0, false
+		case engine.CellHit:
+			if _, sunk := board.ShipAt(p); sunk {
+				return 0, false
+			}
+			weight *= hitWeight
+		}
+	}
+
+	return weight, true
+}
@@ -0,0 +1,44 @@
+package strategy
+
+import (
+	"math/rand"
+	"testing"
+
+	"client.go/engine"
+)
+
+func TestDensityOfLongWoundedShip(t *testing.T) {
+	rules, err := engine.CustomRules(20, 20, []engine.ShipSpec{
+		{Name: "Dreadnought", Size: 14, Count: 1},
+		{Name: "Destroyer", Size: 2, Count: 3},
+	}, engine.NoTouch, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//13 hits make placements covering all of them weighted by hitWeight^13, which doesn't fit int64
+	board := engine.NewBoard(rules)
+	for y := 2; y <= 14; y++ {
+		if err := board.Mark(engine.Point{X: 5, Y: y}, engine.ShotHit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ends := []engine.Point{{X: 5, Y: 1}, {X: 5, Y: 15}}
+
+	density := Density(board)
+	for _, p := range board.Points() {
+		if density[p.X][p.Y] < 0 {
+			t.Fatalf("density of %v is negative: %v", p, density[p.X][p.Y])
+		}
+		if board.At(p) == engine.CellEmpty && p != ends[0] && p != ends[1] && density[p.X][p.Y] >= density[5][1] {
+			t.Errorf("density of %v is %v, not less than %v at end of wounded ship", p, density[p.X][p.Y], density[5][1])
+		}
+	}
+
+	s := NewProbabilityDensity(rand.New(rand.NewSource(1)))
+	for i := 0; i < 10; i++ {
+		if p := s.NextShot(board); p != ends[0] && p != ends[1] {
+			t.Fatalf("NextShot() = %v, want end of wounded ship %v", p, ends)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"math/rand"
+
+	"client.go/engine"
+)
+
+//HuntTarget hunts with random shots on checkerboard cells. When ship is hit, it targets
+//cells next to hit decks, following the ship's orientation once two decks are hit
+type HuntTarget struct {
+	rand *rand.Rand
+}
+
+//Creates new hunt/target strategy
+func NewHuntTarget(r *rand.Rand) *HuntTarget {
+	return &HuntTarget{rand: r}
+}
+
+//Returns cell next to wounded ship, or random checkerboard cell if there is no wounded ship
+func (s *HuntTarget) NextShot(board *engine.Board) engine.Point {
+	if targets := targetCells(board); len(targets) > 0 {
+		return pick(s.rand, targets)
+	}
+
+	return pick(s.rand, emptyCells(board, true))
+}
+
+//Returns empty cells where wounded ship may continue. If two decks of ship are hit,
+//only cells along ship's orientation are returned, unless ship is not straight
+//and there are no such cells
+func targetCells(board *engine.Board) []engine.Point {
+	if targets := adjacentTargets(board, true); len(targets) > 0 {
+		return targets
+	}
+
+	return adjacentTargets(board, false)
+}
+
+//Returns empty cells next to ends of hit decks lines. If 'alongAxis' is true,
+//lines go only along orientation of wounded ship, when it is known
+func adjacentTargets(board *engine.Board, alongAxis bool) []engine.Point {
+	var targets []engine.Point
+	seen := make(map[engine.Point]bool)
+
+	for _, hit := range openHits(board) {
+		directions := []engine.Point{{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1}}
+		if alongAxis {
+			directions = axisDirections(board, hit)
+		}
+
+		for _, d := range directions {
+			//skip over hit decks to the end of ship
+			p := engine.Point{X: hit.X + d.X, Y: hit.Y + d.Y}
+			for board.InBounds(p) && board.At(p) == engine.CellHit {
+				p = engine.Point{X: p.X + d.X, Y: p.Y + d.Y}
+			}
+
+			if board.InBounds(p) && board.At(p) == engine.CellEmpty && !seen[p] {
+				seen[p] = true
+				targets = append(targets, p)
+			}
+		}
+	}
+
+	return targets
+}
+
+//Returns directions in which wounded ship may go from hit deck. If a neighbouring deck
+//is hit too, ship's orientation is known and only two directions are returned
+func axisDirections(board *engine.Board, hit engine.Point) []engine.Point {
+	vertical := []engine.Point{{X: -1, Y: 0}, {X: 1, Y: 0}}
+	horizontal := []engine.Point{{X: 0, Y: -1}, {X: 0, Y: 1}}
+
+	for _, d := range vertical {
+		if p := (engine.Point{X: hit.X + d.X, Y: hit.Y + d.Y}); board.InBounds(p) && board.At(p) == engine.CellHit {
+			return vertical
+		}
+	}
+	for _, d := range horizontal {
+		if p := (engine.Point{X: hit.X + d.X, Y: hit.Y + d.Y}); board.InBounds(p) && board.At(p) == engine.CellHit {
+			return horizontal
+		}
+	}
+
+	return append(vertical, horizontal...)
+}
@@ -0,0 +1,479 @@
+package strategy
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"client.go/engine"
+)
+
+//Limits of work done by Probability, in placements of ships checked. Layouts are enumerated
+//one by one while there are few of them, otherwise random layouts are sampled. Limits keep
+//computation under a few tens of milliseconds even on the largest boards
+const (
+	enumerationBudget = 250000
+	samplingBudget    = 800000
+)
+
+//Returns, for every cell which was not shot yet, probability that it holds a deck of opponent's ship.
+//It is the share of layouts of remaining fleet, which are consistent with results of shots, where the cell
+//holds a deck: ships cover all open hits, don't cover misses or sunk ships and don't break adjacency rule.
+//If there are too many layouts to enumerate them all, layouts are sampled randomly, each weighted
+//by numbers of choices made to build it, so probability is estimated. If no layout is consistent with
+//shots, e.g. ships were reported wrongly, density of placements of single ships is used instead.
+//Cells which were shot have zero probability
+func Probability(board *engine.Board) [][]float64 {
+	l := newLayouts(board)
+	if !l.enumerate(0) {
+		l.clear()
+		l.sample(rand.New(rand.NewSource(1)))
+	}
+
+	if l.total > 0 {
+		for _, p := range board.Points() {
+			l.density[p.X][p.Y] /= l.total
+		}
+		return l.density
+	}
+
+	//share hidden decks by density, so that probabilities still sum up to their number
+	hidden := float64(board.Rules.TotalDecks() - len(l.hits))
+	for _, ship := range board.Fleet.Array {
+		hidden -= float64(ship.Size)
+	}
+	density := Density(board)
+	var empty []engine.Point
+	for _, p := range board.Points() {
+		if board.At(p) == engine.CellEmpty {
+			empty = append(empty, p)
+		} else {
+			density[p.X][p.Y] = 0
+		}
+	}
+	weights := make([]float64, len(empty))
+	for i, p := range empty {
+		weights[i] = density[p.X][p.Y]
+	}
+	fill(weights, hidden)
+	for i, p := range empty {
+		density[p.X][p.Y] = weights[i]
+	}
+
+	return density
+}
+
+//placement is location of one of remaining ships on board. Masks are parts of bitmask of board's cells,
+//where cell x:y is bit x*width+y, starting from word 'word'
+type placement struct {
+	kind  int            //index of ship's kind in remaining fleet
+	cells []engine.Point //decks of ship
+	word  int            //index of the first word of masks in bitmask of board
+	decks []uint64       //mask of decks
+	zone  []uint64       //mask of decks and cells around them, which can't contain decks of another ship
+}
+
+//layouts builds layouts of remaining fleet ship by ship. First ships cover open hits, one hit after another,
+//then the rest of ships are placed kind by kind. When layouts are enumerated, each ship of a kind goes
+//after the previous one in order of placements, so that every layout is built in exactly one way
+type layouts struct {
+	board      *engine.Board
+	hits       []int       //bits of open hits, which must be covered by ships
+	placements []placement //placements of remaining ships which avoid shot cells, grouped by kind
+	first      []int       //index of the first placement of each kind, and number of placements in the end
+	covering   [][]int     //placements covering each open hit
+	left       []int       //number of ships of each kind which are not placed yet
+	last       []int       //the last placement of each kind which was placed after all hits were covered
+	ships      int         //number of ships which are not placed yet
+	placed     []int       //placements of placed ships
+	blocked    [][]uint64  //masks of zones of placed ships, before each placed ship and after the last one
+	options    [][]int     //buffers of choices of the next ship, one for every placed ship
+	work       int         //number of placements checked or added to density
+
+	density [][]float64 //weight of layouts where cell holds a deck, divided by exp(scale)
+	total   float64     //weight of all layouts, divided by exp(scale)
+	scale   float64     //logarithm of the biggest weight of layout
+}
+
+//Finds placements of remaining ships which are consistent with results of shots on board
+func newLayouts(board *engine.Board) *layouts {
+	height, width := board.Rules.Height, board.Rules.Width
+	l := &layouts{board: board}
+	l.density = make([][]float64, height)
+	for x := range l.density {
+		l.density[x] = make([]float64, width)
+	}
+
+	//hit decks around every cell, which can't belong to another ship by adjacency rule
+	hits := openHits(board)
+	touched := make([][][]engine.Point, height)
+	for x := range touched {
+		touched[x] = make([][]engine.Point, width)
+	}
+	hit := make(map[engine.Point]int, len(hits))
+	for i, p := range hits {
+		hit[p] = i
+		l.hits = append(l.hits, p.X*width+p.Y)
+		for _, n := range board.Neighbours(p) {
+			touched[n.X][n.Y] = append(touched[n.X][n.Y], p)
+		}
+	}
+	l.covering = make([][]int, len(hits))
+
+	//long ships go first, they have the least room
+	fleet := remainingFleet(board)
+	sort.SliceStable(fleet, func(i, j int) bool {
+		return len(fleet[i].variants[0]) > len(fleet[j].variants[0])
+	})
+
+	total := 0
+	for _, ships := range fleet {
+		total += len(ships.variants) * height * width
+	}
+	l.placements = make([]placement, 0, total)
+
+	around, seen := make([][][]engine.Point, height), make([][]int, height)
+	for x := range around {
+		around[x], seen[x] = make([][]engine.Point, width), make([]int, width)
+		for y := range around[x] {
+			around[x][y] = board.Neighbours(engine.Point{X: x, Y: y})
+		}
+	}
+	//points and masks of placements are kept in big chunks, which takes fewer allocations
+	var cells, zone, points []engine.Point
+	var masks []uint64
+	for kind, ships := range fleet {
+		l.first = append(l.first, len(l.placements))
+		l.left = append(l.left, ships.count)
+		l.last = append(l.last, len(l.placements)-1)
+		l.ships += ships.count
+
+		for _, shape := range ships.variants {
+			for _, base := range board.Points() {
+				cells = cells[:0]
+				for _, offset := range shape {
+					cells = append(cells, engine.Point{X: base.X + offset.X, Y: base.Y + offset.Y})
+				}
+				if _, ok := placementWeight(board, cells); !ok || touchesHits(cells, touched) {
+					continue
+				}
+
+				//cells are marked by index of placement, so that each of them is added to zone once
+				id := len(l.placements) + 1
+				zone = append(zone[:0], cells...)
+				for _, p := range cells {
+					seen[p.X][p.Y] = id
+				}
+				for _, p := range cells {
+					for _, n := range around[p.X][p.Y] {
+						if seen[n.X][n.Y] != id {
+							seen[n.X][n.Y] = id
+							zone = append(zone, n)
+						}
+					}
+				}
+				word, end := height*width, 0
+				for _, p := range zone {
+					if bit := p.X*width + p.Y; bit/64 < word {
+						word = bit / 64
+					}
+					if bit := p.X*width + p.Y; bit/64 > end {
+						end = bit / 64
+					}
+				}
+				size := end - word + 1
+
+				if cap(masks)-len(masks) < 2*size {
+					masks = make([]uint64, 0, 4096)
+				}
+				start := len(masks)
+				masks = append(masks, make([]uint64, 2*size)...)
+				ship := placement{kind: kind, word: word, decks: masks[start : start+size : start+size], zone: masks[start+size : start+2*size : start+2*size]}
+				for _, p := range zone {
+					bit := p.X*width + p.Y - word*64
+					ship.zone[bit/64] |= 1 << (bit % 64)
+				}
+				for _, p := range cells {
+					bit := p.X*width + p.Y - word*64
+					ship.decks[bit/64] |= 1 << (bit % 64)
+				}
+
+				if cap(points)-len(points) < len(cells) {
+					points = make([]engine.Point, 0, 4096)
+				}
+				start = len(points)
+				points = append(points, cells...)
+				ship.cells = points[start:len(points):len(points)]
+
+				for _, p := range cells {
+					if board.At(p) == engine.CellHit {
+						l.covering[hit[p]] = append(l.covering[hit[p]], len(l.placements))
+					}
+				}
+				l.placements = append(l.placements, ship)
+			}
+		}
+	}
+	l.first = append(l.first, len(l.placements))
+	l.options = make([][]int, l.ships)
+	l.blocked = make([][]uint64, l.ships+1)
+	for i := range l.blocked {
+		l.blocked[i] = make([]uint64, (height*width+63)/64)
+	}
+
+	return l
+}
+
+//Returns index of the first open hit which isn't covered by placed ships, or -1 if all hits are covered.
+//Placements don't touch hits they don't cover, so hit is covered if it is in zone of any placed ship
+func (l *layouts) uncovered() int {
+	blocked := l.blocked[len(l.placed)]
+	for i, bit := range l.hits {
+		if blocked[bit/64]&(1<<(bit%64)) == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//Returns true if placement doesn't collide ships placed already
+func (l *layouts) fits(id int) bool {
+	l.work++
+	ship := &l.placements[id]
+	blocked := l.blocked[len(l.placed)][ship.word:]
+	for i, mask := range ship.decks {
+		if blocked[i]&mask != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Appends placements of the next ship to options. Returns true if the next ship is placed after
+//all hits were covered, then with 'ordered' it goes after the previous ship of its kind
+func (l *layouts) next(options []int, ordered bool) ([]int, bool) {
+	if i := l.uncovered(); i >= 0 {
+		for _, id := range l.covering[i] {
+			if l.left[l.placements[id].kind] > 0 && l.fits(id) {
+				options = append(options, id)
+			}
+		}
+		return options, false
+	}
+
+	for kind, left := range l.left {
+		if left == 0 {
+			continue
+		}
+		id := l.first[kind]
+		if ordered {
+			id = l.last[kind] + 1
+		}
+		for ; id < l.first[kind+1]; id++ {
+			if l.fits(id) {
+				options = append(options, id)
+			}
+		}
+		//there must be a placement for each ship of the kind
+		if len(options) < left {
+			options = options[:0]
+		}
+		break
+	}
+
+	return options, true
+}
+
+//Places the next ship
+func (l *layouts) place(id int, ordered bool) {
+	ship := &l.placements[id]
+	before, after := l.blocked[len(l.placed)], l.blocked[len(l.placed)+1]
+	copy(after, before)
+	for i, mask := range ship.zone {
+		after[ship.word+i] |= mask
+	}
+
+	l.left[ship.kind]--
+	l.ships--
+	l.placed = append(l.placed, id)
+	if ordered {
+		l.last[ship.kind] = id
+	}
+}
+
+//Removes the last placed ship
+func (l *layouts) remove() {
+	id := l.placed[len(l.placed)-1]
+	l.left[l.placements[id].kind]++
+	l.ships++
+	l.placed = l.placed[:len(l.placed)-1]
+}
+
+//Adds decks of placed ships to density with weight exp(logWeight)
+func (l *layouts) add(logWeight float64) {
+	if logWeight > l.scale {
+		factor := math.Exp(l.scale - logWeight)
+		for _, row := range l.density {
+			for y := range row {
+				row[y] *= factor
+			}
+		}
+		l.total *= factor
+		l.scale = logWeight
+	}
+
+	weight := 1.0
+	if logWeight != l.scale {
+		weight = math.Exp(logWeight - l.scale)
+	}
+	for _, id := range l.placed {
+		addPlacement(l.density, l.board, l.placements[id].cells, weight)
+	}
+	l.total += weight
+	l.work += len(l.placed)
+}
+
+//Clears density of layouts
+func (l *layouts) clear() {
+	for _, row := range l.density {
+		for y := range row {
+			row[y] = 0
+		}
+	}
+	l.total, l.scale = 0, 0
+}
+
+//Adds every layout built from placed ships to density. Returns false if enumeration took more than
+//enumerationBudget, then density contains only some of layouts
+func (l *layouts) enumerate(depth int) bool {
+	if l.ships == 0 {
+		if l.uncovered() < 0 {
+			l.add(0)
+		}
+		return true
+	}
+
+	options, ordered := l.next(l.options[depth][:0], true)
+	l.options[depth] = options
+	for _, id := range options {
+		if l.work > enumerationBudget {
+			return false
+		}
+
+		kind := l.placements[id].kind
+		last := l.last[kind]
+		l.place(id, ordered)
+		done := l.enumerate(depth + 1)
+		l.remove()
+		l.last[kind] = last
+		if !done {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Builds random layouts until samplingBudget is spent and adds them to density. Every next ship
+//is chosen uniformly from its placements, so layout is weighted by product of numbers of choices,
+//which makes expected weight of every layout the same. Ships placed after all hits were covered
+//may go in any order, so weight is divided by number of their orders
+func (l *layouts) sample(r *rand.Rand) {
+	for l.work = 0; l.work < samplingBudget; {
+		logWeight, free := 0.0, false
+		for depth := 0; l.ships > 0; depth++ {
+			options, covered := l.next(l.options[depth][:0], false)
+			l.options[depth] = options
+			if len(options) == 0 {
+				break
+			}
+
+			if covered && !free {
+				free = true
+				for _, left := range l.left {
+					orders, _ := math.Lgamma(float64(left + 1))
+					logWeight -= orders
+				}
+			}
+			logWeight += math.Log(float64(len(options)))
+			l.place(options[r.Intn(len(options))], false)
+		}
+		if l.ships == 0 && l.uncovered() < 0 {
+			l.add(logWeight)
+		}
+
+		for len(l.placed) > 0 {
+			l.remove()
+		}
+	}
+}
+
+//Returns true if ship placed in given cells would touch hit deck of another ship
+func touchesHits(cells []engine.Point, touched [][][]engine.Point) bool {
+	for _, p := range cells {
+		for _, hit := range touched[p.X][p.Y] {
+			own := false
+			for _, c := range cells {
+				own = own || c == hit
+			}
+			if !own {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//Adds weight of placement to its cells which were not shot yet
+func addPlacement(density [][]float64, board *engine.Board, cells []engine.Point, weight float64) {
+	for _, p := range cells {
+		if board.At(p) == engine.CellEmpty {
+			density[p.X][p.Y] += weight
+		}
+	}
+}
+
+//Scales weights in place, so that they sum up to 'sum' and none of them exceeds 1.
+//Weights which would exceed 1 are set to 1 and the others are scaled up again
+func fill(weights []float64, sum float64) {
+	capped := make([]bool, len(weights))
+
+	for {
+		rest, total := sum, 0.0
+		for i, w := range weights {
+			if capped[i] {
+				rest--
+			} else {
+				total += w
+			}
+		}
+		if total <= 0 || rest <= 0 {
+			for i := range weights {
+				if !capped[i] {
+					weights[i] = 0
+				}
+			}
+			return
+		}
+
+		scale := rest / total
+		done := true
+		for i, w := range weights {
+			if !capped[i] && w*scale > 1 {
+				weights[i], capped[i] = 1, true
+				done = false
+			}
+		}
+		if done {
+			for i := range weights {
+				if !capped[i] {
+					weights[i] *= scale
+				}
+			}
+			return
+		}
+	}
+}
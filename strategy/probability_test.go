@@ -0,0 +1,221 @@
+package strategy
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"client.go/engine"
+)
+
+func TestProbability(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules engine.Rules
+		shots map[engine.Point]engine.ShotResult
+	}{
+		{"empty board", engine.Classic, nil},
+		{"misses", engine.Classic, map[engine.Point]engine.ShotResult{{X: 0, Y: 0}: engine.ShotMiss, {X: 5, Y: 5}: engine.ShotMiss}},
+		{"wounded ship", engine.Classic, map[engine.Point]engine.ShotResult{{X: 4, Y: 4}: engine.ShotHit}},
+		{"touching ships", engine.MiltonBradley, map[engine.Point]engine.ShotResult{{X: 4, Y: 4}: engine.ShotHit, {X: 4, Y: 5}: engine.ShotHit}},
+		{"sunk ship", engine.Classic, map[engine.Point]engine.ShotResult{{X: 0, Y: 0}: engine.ShotKill, {X: 6, Y: 2}: engine.ShotHit}},
+		{"polyomino", engine.Polyomino, map[engine.Point]engine.ShotResult{{X: 2, Y: 2}: engine.ShotHit, {X: 2, Y: 3}: engine.ShotMiss}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := engine.NewBoard(tt.rules)
+			for p, result := range tt.shots {
+				if err := board.Mark(p, result); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			probability := Probability(board)
+			sum := 0.0
+			for _, p := range board.Points() {
+				v := probability[p.X][p.Y]
+				if v < 0 || v > 1 || math.IsNaN(v) {
+					t.Fatalf("probability of %v is %v", p, v)
+				}
+				if board.At(p) != engine.CellEmpty && v != 0 {
+					t.Errorf("cell %v was shot, but its probability is %v", p, v)
+				}
+				sum += v
+			}
+
+			//every layout has the same number of hidden decks
+			hidden := float64(tt.rules.TotalDecks())
+			for _, result := range tt.shots {
+				if result != engine.ShotMiss {
+					hidden--
+				}
+			}
+			if math.Abs(sum-hidden) > 1e-6 {
+				t.Errorf("probabilities sum up to %v, want %v", sum, hidden)
+			}
+		})
+	}
+}
+
+func TestProbabilityAroundWoundedShip(t *testing.T) {
+	board := engine.NewBoard(engine.Classic)
+	board.Mark(engine.Point{X: 4, Y: 4}, engine.ShotHit)
+	board.Mark(engine.Point{X: 4, Y: 5}, engine.ShotHit)
+
+	probability := Probability(board)
+	//ship goes on in line, it can't turn or touch its hit decks by corner
+	if end := probability[4][3]; end < 0.3 {
+		t.Errorf("probability of end of wounded ship is %v, want at least 0.3", end)
+	}
+	if side := probability[3][4]; side != 0 {
+		t.Errorf("probability of cell beside wounded ship is %v, want 0", side)
+	}
+	if probability[4][3] <= probability[0][9] {
+		t.Errorf("end of wounded ship is less probable than far corner: %v <= %v", probability[4][3], probability[0][9])
+	}
+}
+
+//Returns, for every cell, share of layouts of the whole fleet consistent with shots where the cell holds a deck.
+//Layouts are built with engine's own placement checks. Ships of the fleet must have different kinds
+func bruteForceProbability(t *testing.T, rules engine.Rules, shots map[engine.Point]engine.ShotResult) [][]float64 {
+	t.Helper()
+
+	layout := engine.NewBoard(rules)
+	decks := make([][]float64, rules.Height)
+	for x := range decks {
+		decks[x] = make([]float64, rules.Width)
+	}
+	total := 0.0
+
+	var place func(i int)
+	place = func(i int) {
+		if i == len(rules.Fleet) {
+			for p, result := range shots {
+				if (layout.At(p) == engine.CellDeck) != (result != engine.ShotMiss) {
+					return
+				}
+			}
+			for _, p := range layout.Points() {
+				if _, shot := shots[p]; !shot && layout.At(p) == engine.CellDeck {
+					decks[p.X][p.Y]++
+				}
+			}
+			total++
+			return
+		}
+
+		for _, shape := range rules.Fleet[i].Offsets().Variants() {
+			for _, base := range layout.Points() {
+				ship := engine.NewShapedShip(shape, base)
+				if layout.Place(ship) == nil {
+					place(i + 1)
+					layout.RemoveAt(base)
+				}
+			}
+		}
+	}
+	place(0)
+
+	if total == 0 {
+		t.Fatal("no layout is consistent with shots")
+	}
+	for x := range decks {
+		for y := range decks[x] {
+			decks[x][y] /= total
+		}
+	}
+
+	return decks
+}
+
+func TestProbabilityCountsLayouts(t *testing.T) {
+	tests := []struct {
+		name  string
+		fleet string
+		shots map[engine.Point]engine.ShotResult
+	}{
+		{"empty board", "3,2,1", nil},
+		//ships can't overlap or touch, so cells around the only place of long ship are less probable
+		{"long ship", "4,2,1", map[engine.Point]engine.ShotResult{{X: 0, Y: 2}: engine.ShotMiss, {X: 2, Y: 0}: engine.ShotMiss}},
+		{"hits", "3,L,1", map[engine.Point]engine.ShotResult{{X: 1, Y: 1}: engine.ShotHit, {X: 3, Y: 3}: engine.ShotHit, {X: 2, Y: 2}: engine.ShotMiss}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fleet, err := engine.ParseFleet(tt.fleet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rules, err := engine.CustomRules(5, 5, fleet, engine.NoTouch, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			board := engine.NewBoard(rules)
+			for p, result := range tt.shots {
+				if err := board.Mark(p, result); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want := bruteForceProbability(t, rules, tt.shots)
+
+			//small board is enumerated within budget, so probability is exact
+			got := Probability(board)
+			for _, p := range board.Points() {
+				if math.Abs(got[p.X][p.Y]-want[p.X][p.Y]) > 1e-9 {
+					t.Errorf("probability of %v is %v, want %v", p, got[p.X][p.Y], want[p.X][p.Y])
+				}
+			}
+
+			//sampled layouts are weighted so that they estimate the same probability
+			l := newLayouts(board)
+			l.sample(rand.New(rand.NewSource(1)))
+			for _, p := range board.Points() {
+				if sampled := l.density[p.X][p.Y] / l.total; math.Abs(sampled-want[p.X][p.Y]) > 0.03 {
+					t.Errorf("sampled probability of %v is %v, want %v", p, sampled, want[p.X][p.Y])
+				}
+			}
+		})
+	}
+}
+
+func TestProbabilityIsFast(t *testing.T) {
+	fleet, err := engine.ParseFleet("5,4,4,L,T,O,3,3,3,2,2,2,1,1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := engine.CustomRules(engine.MaxBoardSize, engine.MaxBoardSize, fleet, engine.NoTouch, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the largest board, early in game and after many shots, when layouts are enumerated
+	r := rand.New(rand.NewSource(7))
+	fleetBoard := engine.NewBoard(rules)
+	if err := fleetBoard.PlaceRandomly(r); err != nil {
+		t.Fatal(err)
+	}
+	board := engine.NewBoard(rules)
+	for i, p := range r.Perm(rules.Width * rules.Height) {
+		if i == 10 || i == 400 {
+			start := time.Now()
+			Probability(board)
+			if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+				t.Errorf("probability after %d shots took %v, want less than 50ms", i, elapsed)
+			}
+		}
+
+		shot := engine.Point{X: p / rules.Width, Y: p % rules.Width}
+		if board.At(shot) != engine.CellEmpty {
+			continue
+		}
+		result, err := fleetBoard.Receive(shot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := board.Mark(shot, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
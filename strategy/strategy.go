@@ -0,0 +1,148 @@
+//Package strategy contains shooting strategies. Each strategy chooses next shot
+//using only results of previous shots, recorded on opponent's engine.Board
+package strategy
+
+import (
+	"fmt"
+	"math/rand"
+
+	"client.go/engine"
+)
+
+//Strategy chooses next shot on opponent's board. Board contains results of previous
+//shots and known sunk ships in its fleet. Strategy must return a cell which was not shot yet
+type Strategy interface {
+	NextShot(board *engine.Board) engine.Point
+}
+
+//Names of strategies which can be created by New
+const (
+	RandomName             = "random"
+	HuntTargetName         = "hunt-target"
+	ProbabilityDensityName = "probability"
+)
+
+//Names contains names of all strategies, from the weakest to the strongest
+var Names = []string{RandomName, HuntTargetName, ProbabilityDensityName}
+
+//Creates strategy by its name. Given source of randomness is used to break ties
+func New(name string, r *rand.Rand) (Strategy, error) {
+	switch name {
+	case RandomName:
+		return NewRandom(r), nil
+	case HuntTargetName:
+		return NewHuntTarget(r), nil
+	case ProbabilityDensityName:
+		return NewProbabilityDensity(r), nil
+	}
+
+	return nil, fmt.Errorf("unknown strategy %q", name)
+}
+
+//Chooses up to n different cells for salvo. Results of salvo are unknown until it is fired,
+//so every next cell is chosen as if previous cells of salvo were missed
+func Salvo(s Strategy, board *engine.Board, n int) []engine.Point {
+	planned := board.Clone()
+	shots := make([]engine.Point, 0, n)
+
+	for len(shots) < n {
+		p := s.NextShot(planned)
+		if planned.At(p) != engine.CellEmpty {
+			break //there are no cells left to shoot
+		}
+
+		planned.Mark(p, engine.ShotMiss)
+		shots = append(shots, p)
+	}
+
+	return shots
+}
+
+//Random shoots random cells which were not shot yet
+type Random struct {
+	rand *rand.Rand
+}
+
+//Creates new random strategy
+func NewRandom(r *rand.Rand) *Random {
+	return &Random{rand: r}
+}
+
+//Returns random empty cell
+func (s *Random) NextShot(board *engine.Board) engine.Point {
+	return pick(s.rand, emptyCells(board, false))
+}
+
+//Returns all cells which were not shot yet. If 'parity' is true,
+//returns only cells of one color of checkerboard, if there are any
+func emptyCells(board *engine.Board, parity bool) []engine.Point {
+	var cells, even []engine.Point
+
+	for _, p := range board.Points() {
+		if board.At(p) != engine.CellEmpty {
+			continue
+		}
+
+		cells = append(cells, p)
+		if (p.X+p.Y)%2 == 0 {
+			even = append(even, p)
+		}
+	}
+
+	if parity && len(even) > 0 {
+		return even
+	}
+
+	return cells
+}
+
+//Returns hit decks of ships which are not sunk yet
+func openHits(board *engine.Board) []engine.Point {
+	var hits []engine.Point
+
+	for _, p := range board.Points() {
+		if board.At(p) != engine.CellHit {
+			continue
+		}
+		if _, sunk := board.ShipAt(p); !sunk {
+			hits = append(hits, p)
+		}
+	}
+
+	return hits
+}
+
+//remainingShips describes ships of one kind which are not sunk yet
+type remainingShips struct {
+	variants []engine.Shape //all rotations and mirrors of ship's shape
+	count    int
+}
+
+//Returns ships of each kind which are not sunk yet
+func remainingFleet(board *engine.Board) []remainingShips {
+	sunk := make(map[string]int)
+	for _, ship := range board.Fleet.Array {
+		sunk[ship.Kind()]++
+	}
+
+	var remaining []remainingShips
+	counts := board.Rules.CountByKind()
+	for _, spec := range board.Rules.Fleet {
+		kind := spec.Kind()
+		if left := counts[kind] - sunk[kind]; left > 0 {
+			remaining = append(remaining, remainingShips{variants: spec.Offsets().Variants(), count: left})
+		}
+		delete(counts, kind) //ships of the same kind may be described by several specs
+	}
+
+	return remaining
+}
+
+//Returns random point from slice. Returns zero point if slice is empty
+func pick(r *rand.Rand, points []engine.Point) engine.Point {
+	if len(points) == 0 {
+		return engine.Point{}
+	}
+
+	return points[r.Intn(len(points))]
+}
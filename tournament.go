@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"client.go/engine"
+	"client.go/strategy"
+	"client.go/tournament"
+)
+
+//Runs 'tournament' subcommand: plays games between two strategies without GUI
+//and writes statistics as CSV or JSON
+func runTournament(args []string) error {
+	flags := flag.NewFlagSet("tournament", flag.ContinueOnError)
+	games := flags.Int("n", 1000, "number of games")
+	first := flags.String("a", strategy.HuntTargetName, "first strategy: "+strings.Join(strategy.Names, ", "))
+	second := flags.String("b", strategy.ProbabilityDensityName, "second strategy: "+strings.Join(strategy.Names, ", "))
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of strategies' random numbers and fleet placement")
+	format := flags.String("format", "csv", "output format: csv or json")
+	out := flags.String("out", "", "output file; standard output if empty")
+	heatmap := flags.String("heatmap", "", "file for per-cell hit heatmaps in CSV format")
+	rulesName := flags.String("rules", engine.Classic.Name, "preset rules: "+strings.Join(presetNames(), ", "))
+	fleet := flags.String("fleet", "", "comma separated ship sizes of custom rules, e.g. 5,4,3,3,2; overrides -rules")
+	width := flags.Int("width", 10, "board width of custom rules")
+	height := flags.Int("height", 10, "board height of custom rules")
+	salvo := flags.String("salvo", "1", "shots per turn of custom rules: number, or \"ships\" for one shot per surviving ship")
+	adjacency := flags.String("adjacency", engine.NoTouch.String(), "contacts between ships of custom rules: "+strings.Join(engine.AdjacencyNames[:], ", "))
+	placementA := flags.String("placement-a", engine.StyleUniform.String(), "fleet placement style of first strategy: "+strings.Join(engine.StyleNames[:], ", "))
+	placementB := flags.String("placement-b", engine.StyleUniform.String(), "fleet placement style of second strategy: "+strings.Join(engine.StyleNames[:], ", "))
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	rules, ok := engine.PresetByName(*rulesName)
+	if *fleet != "" {
+		ships, err := engine.ParseFleet(*fleet)
+		if err != nil {
+			return err
+		}
+		a, err := engine.ParseAdjacency(*adjacency)
+		if err != nil {
+			return err
+		}
+		shots, err := engine.ParseSalvo(*salvo)
+		if err != nil {
+			return err
+		}
+		if rules, err = engine.CustomRules(*width, *height, ships, a, shots); err != nil {
+			return err
+		}
+	} else if !ok {
+		return fmt.Errorf("unknown rules %q", *rulesName)
+	}
+
+	var placement [2]engine.PlacementStyle
+	for i, name := range []string{*placementA, *placementB} {
+		style, err := engine.ParseStyle(name)
+		if err != nil {
+			return err
+		}
+		placement[i] = style
+	}
+
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	result, err := tournament.Run(tournament.Config{
+		Games:      *games,
+		Strategies: [2]string{*first, *second},
+		Seed:       *seed,
+		Rules:      rules,
+		Placement:  placement,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(*out, func(w io.Writer) error {
+		if *format == "json" {
+			return tournament.WriteJSON(w, result)
+		}
+		return tournament.WriteCSV(w, result)
+	})
+	if err != nil || *heatmap == "" {
+		return err
+	}
+
+	return writeFile(*heatmap, func(w io.Writer) error {
+		return tournament.WriteHeatmapCSV(w, result)
+	})
+}
+
+//Calls 'write' with file located at 'path', or with standard output if path is empty
+func writeFile(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+//Returns names of preset rules
+func presetNames() []string {
+	names := make([]string, 0, len(engine.Presets))
+	for _, rules := range engine.Presets {
+		names = append(names, rules.Name)
+	}
+
+	return names
+}
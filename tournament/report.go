@@ -0,0 +1,65 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+//Writes result as indented JSON, including heatmaps
+func WriteJSON(w io.Writer, result Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(result)
+}
+
+//Writes one CSV row with summary for each strategy
+func WriteCSV(w io.Writer, result Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"strategy", "games", "wins", "win_rate", "win_rate_ci_low", "win_rate_ci_high",
+		"mean_shots_to_win", "shots_to_win_ci_low", "shots_to_win_ci_high", "shots", "hits",
+	})
+
+	for _, stats := range result.Players {
+		writer.Write([]string{
+			stats.Strategy,
+			strconv.Itoa(result.Games),
+			strconv.Itoa(stats.Wins),
+			formatFloat(stats.WinRate),
+			formatFloat(stats.WinRateCI[0]),
+			formatFloat(stats.WinRateCI[1]),
+			formatFloat(stats.MeanShotsToWin),
+			formatFloat(stats.ShotsToWinCI[0]),
+			formatFloat(stats.ShotsToWinCI[1]),
+			strconv.Itoa(stats.Shots),
+			strconv.Itoa(stats.Hits),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+//Writes heatmaps of both strategies as CSV rows: strategy, x, y, hits
+func WriteHeatmapCSV(w io.Writer, result Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"strategy", "x", "y", "hits"})
+
+	for _, stats := range result.Players {
+		for x := range stats.Heatmap {
+			for y, hits := range stats.Heatmap[x] {
+				writer.Write([]string{stats.Strategy, strconv.Itoa(x), strconv.Itoa(y), strconv.Itoa(hits)})
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,199 @@
+//Package tournament plays games between two shooting strategies without GUI
+//and collects statistics to compare them
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"client.go/engine"
+	"client.go/strategy"
+)
+
+//z-score of 95% confidence intervals
+const z95 = 1.96
+
+//Config describes tournament between two strategies. Games are played
+//by engine.Classic rules if Rules are not set
+type Config struct {
+	Games      int
+	Strategies [2]string
+	Seed       int64
+	Rules      engine.Rules
+	Placement  [2]engine.PlacementStyle //styles of players' fleets, uniform by default
+}
+
+//Stats contains results of one strategy
+type Stats struct {
+	Strategy       string
+	Wins           int
+	WinRate        float64
+	WinRateCI      [2]float64 //95% Wilson score interval
+	MeanShotsToWin float64
+	ShotsToWinCI   [2]float64 //95% confidence interval of mean
+	Shots          int        //shots fired in all games
+	Hits           int        //hits and kills in all games
+	//number of hits in each cell of opponent's board
+	Heatmap [][]int
+}
+
+//Result contains results of whole tournament
+type Result struct {
+	Games   int
+	Seed    int64
+	Rules   string //name of rule set
+	Players [2]Stats
+}
+
+//Plays cfg.Games games between two strategies. Every game both fleets are placed randomly
+//in placement styles of players; players start games in turn. Player keeps turn after hit or kill, unless rules are salvo ones
+func Run(cfg Config) (Result, error) {
+	if cfg.Games <= 0 {
+		return Result{}, errors.New("number of games should be positive")
+	}
+
+	rules := cfg.Rules
+	if rules.Fleet == nil {
+		rules = engine.Classic
+	}
+	if err := rules.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	r := rand.New(rand.NewSource(cfg.Seed))
+	var players [2]strategy.Strategy
+	var generators [2]*engine.Generator
+	result := Result{Games: cfg.Games, Seed: cfg.Seed, Rules: rules.Name}
+
+	for i, name := range cfg.Strategies {
+		s, err := strategy.New(name, r)
+		if err != nil {
+			return Result{}, err
+		}
+		players[i] = s
+		generators[i] = engine.NewGenerator(cfg.Placement[i], r)
+		result.Players[i].Strategy = name
+		result.Players[i].Heatmap = make([][]int, rules.Height)
+		for x := range result.Players[i].Heatmap {
+			result.Players[i].Heatmap[x] = make([]int, rules.Width)
+		}
+	}
+
+	var shotsToWin [2][]int
+	for game := 0; game < cfg.Games; game++ {
+		winner, shots, err := play(players, generators, rules, game%2, &result)
+		if err != nil {
+			return Result{}, fmt.Errorf("game %d: %w", game+1, err)
+		}
+
+		result.Players[winner].Wins++
+		shotsToWin[winner] = append(shotsToWin[winner], shots)
+	}
+
+	for i := range result.Players {
+		stats := &result.Players[i]
+		stats.WinRate = float64(stats.Wins) / float64(cfg.Games)
+		stats.WinRateCI = wilsonInterval(stats.Wins, cfg.Games)
+		stats.MeanShotsToWin, stats.ShotsToWinCI = meanInterval(shotsToWin[i])
+	}
+
+	return result, nil
+}
+
+//Plays one game and returns index of winner and number of shots winner fired
+func play(players [2]strategy.Strategy, generators [2]*engine.Generator, rules engine.Rules, first int,
+	result *Result) (int, int, error) {
+	var fleets, targets [2]*engine.Board
+	var shots [2]int
+
+	for i := range fleets {
+		fleets[i] = engine.NewBoard(rules)
+		if err := generators[i].Place(fleets[i]); err != nil {
+			return 0, 0, err
+		}
+		targets[i] = engine.NewBoard(rules)
+	}
+
+	turn := first
+	for {
+		opponent := 1 - turn
+		if shots[turn] >= rules.Width*rules.Height {
+			return 0, 0, fmt.Errorf("%s didn't finish game", result.Players[turn].Strategy)
+		}
+
+		salvo := strategy.Salvo(players[turn], targets[turn], rules.ShotsPerTurn(fleets[turn].Fleet))
+		keepTurn := false
+
+		for _, p := range salvo {
+			//cell of salvo may be covered by ship killed earlier in the same salvo, such shot is missed
+			shot, err := fleets[opponent].Receive(p)
+			if errors.Is(err, engine.ErrAlreadyShot) && rules.IsSalvo() {
+				shot, err = engine.ShotMiss, nil
+			}
+			if err != nil {
+				return 0, 0, fmt.Errorf("%s shot (%d, %d): %w", result.Players[turn].Strategy, p.X, p.Y, err)
+			}
+			targets[turn].Mark(p, shot)
+
+			shots[turn]++
+			stats := &result.Players[turn]
+			stats.Shots++
+
+			if shot == engine.ShotMiss {
+				continue
+			}
+
+			stats.Hits++
+			stats.Heatmap[p.X][p.Y]++
+
+			if fleets[opponent].FleetDestroyed() {
+				return turn, shots[turn], nil
+			}
+			keepTurn = !rules.IsSalvo()
+		}
+
+		if !keepTurn {
+			turn = opponent
+		}
+	}
+}
+
+//Returns 95% Wilson score interval of proportion successes/total
+func wilsonInterval(successes int, total int) [2]float64 {
+	n := float64(total)
+	p := float64(successes) / n
+
+	denominator := 1 + z95*z95/n
+	center := (p + z95*z95/(2*n)) / denominator
+	margin := z95 * math.Sqrt(p*(1-p)/n+z95*z95/(4*n*n)) / denominator
+
+	return [2]float64{math.Max(0, center-margin), math.Min(1, center+margin)}
+}
+
+//Returns mean of values and its 95% confidence interval. Interval has zero width if there are less than two values
+func meanInterval(values []int) (float64, [2]float64) {
+	if len(values) == 0 {
+		return 0, [2]float64{}
+	}
+
+	n := float64(len(values))
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	mean := sum / n
+
+	if len(values) < 2 {
+		return mean, [2]float64{mean, mean}
+	}
+
+	var squares float64
+	for _, v := range values {
+		squares += (float64(v) - mean) * (float64(v) - mean)
+	}
+	margin := z95 * math.Sqrt(squares/(n-1)) / math.Sqrt(n)
+
+	return mean, [2]float64{mean - margin, mean + margin}
+}
@@ -0,0 +1,87 @@
+package tournament
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"client.go/engine"
+	"client.go/strategy"
+)
+
+func TestRunIsDeterministic(t *testing.T) {
+	configs := []Config{
+		{Games: 20, Strategies: [2]string{strategy.HuntTargetName, strategy.ProbabilityDensityName}, Seed: 7},
+		{Games: 10, Strategies: [2]string{strategy.RandomName, strategy.HuntTargetName}, Seed: 7, Rules: engine.MiltonBradley,
+			Placement: [2]engine.PlacementStyle{engine.StyleSpread, engine.StyleEdges}},
+	}
+
+	for _, cfg := range configs {
+		t.Run(cfg.Strategies[0]+"/"+cfg.Strategies[1], func(t *testing.T) {
+			first, err := Run(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := Run(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("results of tournaments with seed %d differ:\n%+v\n%+v", cfg.Seed, first, second)
+			}
+
+			//reports are compared too, since they are what user diffs between runs
+			var a, b bytes.Buffer
+			if err := WriteJSON(&a, first); err != nil {
+				t.Fatal(err)
+			}
+			if err := WriteJSON(&b, second); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(a.Bytes(), b.Bytes()) {
+				t.Errorf("JSON reports differ:\n%s\n%s", a.Bytes(), b.Bytes())
+			}
+
+			if first.Players[0].Wins+first.Players[1].Wins != cfg.Games {
+				t.Errorf("players won %d games of %d", first.Players[0].Wins+first.Players[1].Wins, cfg.Games)
+			}
+		})
+	}
+}
+
+func TestRunDependsOnSeed(t *testing.T) {
+	cfg := Config{Games: 20, Strategies: [2]string{strategy.RandomName, strategy.RandomName}, Seed: 1}
+	first, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Seed = 2
+	second, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reflect.DeepEqual(first.Players, second.Players) {
+		t.Errorf("tournaments with different seeds have the same results")
+	}
+}
+
+func TestRunRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"no games", Config{Strategies: [2]string{strategy.RandomName, strategy.RandomName}}},
+		{"unknown strategy", Config{Games: 1, Strategies: [2]string{strategy.RandomName, "cheater"}}},
+		{"invalid rules", Config{Games: 1, Strategies: [2]string{strategy.RandomName, strategy.RandomName},
+			Rules: engine.Rules{Name: "Empty", Width: 10, Height: 10, Fleet: []engine.ShipSpec{}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Run(tt.cfg); err == nil {
+				t.Errorf("Run() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+//Width of sidebar with opponent's fleet in game container
+const trackerWidth = 130
+
+var trackerRows *fyne.Container //rows of opponent's ships in sidebar, nil when game container isn't shown
+var trackerLabel *widget.Label  //number of opponent's ships afloat
+
+//Returns sidebar of given height, which lists opponent's fleet by size. It is filled by updateFleetTracker
+func newFleetTracker(height float32) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle("Enemy fleet", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	trackerLabel = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+	trackerRows = container.NewVBox()
+
+	tracker := container.NewBorder(container.NewVBox(title, trackerLabel), nil, nil, nil, container.NewVScroll(trackerRows))
+	tracker.Resize(fyne.NewSize(trackerWidth, height))
+	updateFleetTracker()
+
+	return tracker
+}
+
+//Lists every ship of opponent's fleet, the biggest first, with sunk ships crossed out.
+//Ships are known as sunk from kills on bot's field, so it is called whenever the field is rendered
+func updateFleetTracker() {
+	if trackerRows == nil {
+		return
+	}
+
+	status := botBoard.FleetStatus()
+	//rules list ship types in any order, sidebar shows the biggest ships first
+	sort.SliceStable(status, func(i, j int) bool { return status[i].Spec.Size > status[j].Spec.Size })
+
+	afloat := 0
+	trackerRows.Objects = nil
+	for _, s := range status {
+		afloat += s.Spec.Count - s.Sunk
+		for i := 0; i < s.Spec.Count; i++ {
+			trackerRows.Add(trackerRow(fmt.Sprintf("%s (%d)", s.Spec.Name, s.Spec.Size), i < s.Sunk))
+		}
+	}
+	trackerRows.Refresh()
+	trackerLabel.SetText(fmt.Sprintf("%d of %d afloat", afloat, botBoard.Rules.ShipCount()))
+}
+
+//Returns row of sidebar with name of ship, which is crossed out if ship is sunk
+func trackerRow(name string, sunk bool) fyne.CanvasObject {
+	label := widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{Italic: sunk})
+	if !sunk {
+		return label
+	}
+
+	strike := canvas.NewRectangle(theme.ForegroundColor())
+	strike.SetMinSize(fyne.NewSize(fyne.MeasureText(name, theme.TextSize(), label.TextStyle).Width, 2))
+
+	return container.NewMax(label, container.NewCenter(strike))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"client.go/api"
+	"client.go/engine"
+	"client.go/fairplay"
+	"client.go/record"
+	"fyne.io/fyne/v2/dialog"
+)
+
+//revealer is implemented by backends which support commit-reveal verification of fleets
+type revealer interface {
+	Reveal(ctx context.Context, gameID string, reveal api.RevealData) (api.RevealData, error)
+}
+
+var fleetSalt []byte       //salt of user's fleet commitment in current game
+var fleetCommitment string //commitment of user's fleet sent to opponent in current game
+
+//Builds request for new game or room. It contains user's fleet and its commitment
+func newGameRequest(username string, gameID string) (api.NewGame, error) {
+	salt, err := fairplay.NewSalt()
+	if err != nil {
+		return api.NewGame{}, err
+	}
+	fleetSalt = salt
+	fleetCommitment = fairplay.Commit(userBoard.Fleet.Array, salt)
+
+	return api.NewGame{
+		GameID:     gameID,
+		Username:   username,
+		Rules:      userBoard.Rules,
+		Fleet:      api.FleetData(userBoard.Fleet),
+		Commitment: fleetCommitment,
+	}, nil
+}
+
+//Records user's shot with result reported by opponent
+func recordUserShot(p engine.Point, result engine.ShotResult) {
+	gameRecord.Add(record.User, p, result)
+}
+
+//Records opponent's shot with result resolved on user's field
+func recordBotShot(p engine.Point, result engine.ShotResult) {
+	gameRecord.Add(record.Opponent, p, result)
+}
+
+//Reveals user's fleet to opponent and checks opponent's revealed fleet against its commitment
+//and user's shots. User's own field is checked against commitment sent at the start of the game
+//and opponent's shots too, so fleet changed during the game is caught. Result is shown in dialog.
+//Nothing is done if opponent didn't publish commitment
+func verifyFleets() {
+	reveal, ok := backend.(revealer)
+	if !ok || gameData.BotCommitment == "" {
+		return
+	}
+
+	err := auditOpponent(reveal)
+	if err == nil {
+		err = fairplay.Audit(fleetCommitment, userBoard.Rules, userBoard.Fleet.Array, fleetSalt,
+			gameRecord.Shots(record.Opponent))
+		if err != nil {
+			err = fmt.Errorf("user's field: %w", err)
+		}
+	}
+
+	var cheating *fairplay.CheatingError
+	switch {
+	case err == nil:
+		dialog.ShowInformation("Fair play", "Opponent's fleet matches its commitment and every shot", mainWindow)
+	case errors.As(err, &cheating), errors.Is(err, fairplay.ErrCommitmentMismatch):
+		fmt.Println(err)
+		dialog.ShowError(fmt.Errorf("Cheating detected: %w", err), mainWindow)
+	default:
+		showError(err)
+	}
+}
+
+//Exchanges revealed fleets with opponent and audits opponent's one
+func auditOpponent(reveal revealer) error {
+	opponent, err := reveal.Reveal(context.Background(), gameData.GameID, api.RevealData{
+		Fleet: api.FleetData(userBoard.Fleet),
+		Salt:  hex.EncodeToString(fleetSalt),
+	})
+	if err != nil {
+		return err
+	}
+
+	ships, err := api.ShipsFromData(opponent.Fleet)
+	if err != nil {
+		return fmt.Errorf("opponent's fleet: %w", err)
+	}
+	salt, err := hex.DecodeString(opponent.Salt)
+	if err != nil {
+		return fmt.Errorf("opponent's salt: %w", err)
+	}
+
+	if err := fairplay.Audit(gameData.BotCommitment, botBoard.Rules, ships, salt, gameRecord.Shots(record.User)); err != nil {
+		return fmt.Errorf("opponent's fleet: %w", err)
+	}
+	gameRecord.OpponentFleet = ships
+
+	return nil
+}